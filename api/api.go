@@ -0,0 +1,151 @@
+package api
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/drifterz13/dino-noti/export"
+	"github.com/drifterz13/dino-noti/service"
+)
+
+const (
+	defaultLimit = 50
+	maxLimit     = 500
+	// maxBodySize bounds request bodies, which are all small JSON objects.
+	maxBodySize = 1 << 20
+)
+
+// Handler serves the admin REST API under /api/. Every request must carry
+// the admin token as a bearer token.
+type Handler struct {
+	srv   *service.Service
+	token string
+	mux   *http.ServeMux
+}
+
+func NewHandler(srv *service.Service, token string) *Handler {
+	h := &Handler{srv: srv, token: token, mux: http.NewServeMux()}
+
+	h.mux.HandleFunc("GET /api/watchlist", h.listWatchlist)
+	h.mux.HandleFunc("POST /api/watchlist", h.addWatch)
+	h.mux.HandleFunc("DELETE /api/watchlist/{source}/{model}", h.removeWatch)
+
+	h.mux.HandleFunc("GET /api/targets", h.listTargets)
+	h.mux.HandleFunc("POST /api/targets", h.addTarget)
+	h.mux.HandleFunc("PUT /api/targets/{id}", h.updateTarget)
+	h.mux.HandleFunc("DELETE /api/targets/{id}", h.deleteTarget)
+
+	h.mux.HandleFunc("GET /api/runs", h.listRuns)
+	h.mux.HandleFunc("POST /api/runs", h.triggerRun)
+	h.mux.HandleFunc("GET /api/runs/{id}", h.getRun)
+	h.mux.HandleFunc("GET /api/runs/{id}/export", h.exportRun)
+	h.mux.HandleFunc("GET /api/jobs/dead", h.listDeadJobs)
+
+	h.mux.HandleFunc("GET /api/listings", h.listListings)
+	h.mux.HandleFunc("GET /api/listings/{id}/prices", h.listingPrices)
+	h.mux.HandleFunc("GET /api/models/{model}/prices", h.modelPrices)
+	h.mux.HandleFunc("GET /api/models/{model}/stats", h.modelStats)
+	h.mux.HandleFunc("GET /api/export/listings", h.exportListings)
+
+	h.mux.HandleFunc("GET /api/subscribers", h.listSubscribers)
+	h.mux.HandleFunc("GET /api/subscribers/{id}", h.getSubscriber)
+	h.mux.HandleFunc("PATCH /api/subscribers/{id}", h.updateSubscriber)
+	h.mux.HandleFunc("GET /api/subscribers/{id}/feed", h.getFeed)
+	h.mux.HandleFunc("POST /api/subscribers/{id}/feed", h.rotateFeed)
+
+	return h
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !h.authorized(r) {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="dino-noti"`)
+		writeError(w, http.StatusUnauthorized, "invalid or missing token")
+		return
+	}
+	h.mux.ServeHTTP(w, r)
+}
+
+func (h *Handler) authorized(r *http.Request) bool {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && subtle.ConstantTimeCompare([]byte(token), []byte(h.token)) == 1
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		slog.Error("Error writing API response", "err", err)
+	}
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, errorResponse{Error: message})
+}
+
+// writeServiceError maps service errors to a status code. Unexpected errors
+// are logged rather than shown to the client.
+func writeServiceError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrNotFound):
+		writeError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, service.ErrInvalidInput):
+		writeError(w, http.StatusBadRequest, err.Error())
+	default:
+		slog.Error("Error handling API request", "err", err)
+		writeError(w, http.StatusInternalServerError, "internal error")
+	}
+}
+
+func decodeJSON(w http.ResponseWriter, r *http.Request, v any) bool {
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err))
+		return false
+	}
+	return true
+}
+
+func pathID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid id")
+		return 0, false
+	}
+	return id, true
+}
+
+func queryLimit(w http.ResponseWriter, r *http.Request) (int, bool) {
+	value := r.URL.Query().Get("limit")
+	if value == "" {
+		return defaultLimit, true
+	}
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 1 || limit > maxLimit {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("limit must be between 1 and %d", maxLimit))
+		return 0, false
+	}
+	return limit, true
+}
+
+// queryTime accepts an RFC 3339 timestamp or a plain date in the configured
+// time zone. A missing parameter is the zero time.
+func (h *Handler) queryTime(w http.ResponseWriter, r *http.Request, name string) (time.Time, bool) {
+	t, err := export.ParseTime(r.URL.Query().Get(name), h.srv.Location())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("%s: %s", name, err))
+		return time.Time{}, false
+	}
+	return t, true
+}
@@ -0,0 +1,140 @@
+package api
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/drifterz13/dino-noti/export"
+	"github.com/drifterz13/dino-noti/model"
+)
+
+// exportListings writes the listings within the filter, or those on a chat's
+// watchlist with ?source=, in the requested format. Without a limit every
+// listing is exported.
+func (h *Handler) exportListings(w http.ResponseWriter, r *http.Request) {
+	format, ok := queryFormat(w, r)
+	if !ok {
+		return
+	}
+	filter := model.ListingFilter{Model: r.URL.Query().Get("model")}
+	if r.URL.Query().Has("limit") {
+		if filter.Limit, ok = queryLimit(w, r); !ok {
+			return
+		}
+	}
+	if filter.Since, ok = h.queryTime(w, r, "since"); !ok {
+		return
+	}
+	if filter.Until, ok = h.queryTime(w, r, "until"); !ok {
+		return
+	}
+
+	var (
+		listings []model.Listing
+		err      error
+	)
+	title := "dino-noti listings"
+	if source := r.URL.Query().Get("source"); source != "" {
+		listings, err = h.srv.WatchlistListings(source, filter)
+		title = fmt.Sprintf("dino-noti watchlist of %s", source)
+	} else {
+		listings, err = h.srv.Listings(filter)
+	}
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	if filter.Model != "" {
+		title += " · " + filter.Model
+	}
+
+	writeExport(w, r, format, "listings", title, export.FromListings(listings))
+}
+
+// exportRun writes the matched items of a run in the requested format.
+func (h *Handler) exportRun(w http.ResponseWriter, r *http.Request) {
+	format, ok := queryFormat(w, r)
+	if !ok {
+		return
+	}
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	details, items, err := h.srv.RunDetails(id)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	items = matchedOnly(items)
+	if name := r.URL.Query().Get("model"); name != "" {
+		var filtered []model.MatchedItem
+		for _, item := range items {
+			if strings.EqualFold(item.MatchedName, name) {
+				filtered = append(filtered, item)
+			}
+		}
+		items = filtered
+	}
+
+	writeExport(w, r, format, "run-"+strconv.FormatInt(id, 10), fmt.Sprintf("dino-noti run %d", id),
+		export.FromMatchedItems(items, details.StartedAt))
+}
+
+func writeExport(w http.ResponseWriter, r *http.Request, format export.Format, name, title string, items []export.Item) {
+	w.Header().Set("Content-Type", format.ContentType())
+	if format == export.CSV || format == export.JSONL {
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, name, format))
+	}
+	feed := export.Feed{Title: title, SelfURL: r.URL.String()}
+	if err := export.Write(w, format, feed, items); err != nil {
+		slog.Error("Error writing export", "err", err)
+	}
+}
+
+// queryFormat reads the export format, which defaults to CSV.
+func queryFormat(w http.ResponseWriter, r *http.Request) (export.Format, bool) {
+	value := r.URL.Query().Get("format")
+	if value == "" {
+		return export.CSV, true
+	}
+	format, err := export.ParseFormat(value)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return "", false
+	}
+	return format, true
+}
+
+type feedResponse struct {
+	Token string            `json:"token"`
+	URLs  map[string]string `json:"urls"`
+}
+
+func (h *Handler) getFeed(w http.ResponseWriter, r *http.Request) {
+	h.writeFeed(w, r.PathValue("id"), false)
+}
+
+// rotateFeed replaces the feed token of a chat, breaking its old feed URLs.
+func (h *Handler) rotateFeed(w http.ResponseWriter, r *http.Request) {
+	h.writeFeed(w, r.PathValue("id"), true)
+}
+
+func (h *Handler) writeFeed(w http.ResponseWriter, id string, rotate bool) {
+	token, err := h.srv.FeedToken(id, rotate)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	out := feedResponse{Token: token, URLs: map[string]string{}}
+	for _, format := range export.Formats {
+		out.URLs[string(format)] = h.srv.FeedURL(token, format)
+	}
+	writeJSON(w, http.StatusOK, out)
+}
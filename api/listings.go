@@ -0,0 +1,102 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/drifterz13/dino-noti/model"
+)
+
+const defaultHistoryDays = 90
+
+func (h *Handler) listListings(w http.ResponseWriter, r *http.Request) {
+	limit, ok := queryLimit(w, r)
+	if !ok {
+		return
+	}
+	since, ok := h.queryTime(w, r, "since")
+	if !ok {
+		return
+	}
+	until, ok := h.queryTime(w, r, "until")
+	if !ok {
+		return
+	}
+
+	listings, err := h.srv.Listings(model.ListingFilter{
+		Model: r.URL.Query().Get("model"),
+		Since: since,
+		Until: until,
+		Limit: limit,
+	})
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	out := []listing{}
+	for _, l := range listings {
+		out = append(out, newListing(l))
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+type listingPricesResponse struct {
+	listing
+	Prices []pricePoint `json:"prices"`
+}
+
+func (h *Handler) listingPrices(w http.ResponseWriter, r *http.Request) {
+	l, prices, err := h.srv.ListingPrices(r.PathValue("id"))
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, listingPricesResponse{listing: newListing(*l), Prices: newPricePoints(prices)})
+}
+
+type modelPricesResponse struct {
+	Model  string       `json:"model"`
+	Days   int          `json:"days"`
+	Prices []pricePoint `json:"prices"`
+}
+
+func (h *Handler) modelPrices(w http.ResponseWriter, r *http.Request) {
+	days := defaultHistoryDays
+	if value := r.URL.Query().Get("days"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			writeError(w, http.StatusBadRequest, "days must be a positive number")
+			return
+		}
+		days = n
+	}
+
+	name, prices, err := h.srv.ModelPriceHistory(r.PathValue("model"), time.Now().AddDate(0, 0, -days))
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, modelPricesResponse{Model: name, Days: days, Prices: newPricePoints(prices)})
+}
+
+type modelStatsResponse struct {
+	Model   string       `json:"model"`
+	Windows []priceStats `json:"windows"`
+}
+
+func (h *Handler) modelStats(w http.ResponseWriter, r *http.Request) {
+	stats, err := h.srv.PriceStats(r.PathValue("model"))
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	out := modelStatsResponse{Windows: []priceStats{}}
+	for _, s := range stats {
+		out.Model = s.Model
+		out.Windows = append(out.Windows, priceStats{Days: s.Days, Count: s.Count, Min: s.Min, Median: s.Median, Max: s.Max})
+	}
+	writeJSON(w, http.StatusOK, out)
+}
@@ -0,0 +1,107 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/drifterz13/dino-noti/model"
+)
+
+func (h *Handler) listRuns(w http.ResponseWriter, r *http.Request) {
+	limit, ok := queryLimit(w, r)
+	if !ok {
+		return
+	}
+
+	runs, err := h.srv.Runs(limit)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	out := []run{}
+	for _, r := range runs {
+		out = append(out, newRun(r))
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+type triggerResponse struct {
+	JobID int64 `json:"job_id"`
+}
+
+// triggerRun queues a run, or joins the one already queued.
+func (h *Handler) triggerRun(w http.ResponseWriter, r *http.Request) {
+	id, err := h.srv.EnqueueRun(r.Context(), model.TriggerAPI, nil)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusAccepted, triggerResponse{JobID: id})
+}
+
+func (h *Handler) getRun(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	details, items, err := h.srv.RunDetails(id)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	out := runDetails{run: newRun(*details), Items: []runItem{}}
+	if r.URL.Query().Get("matched") == "true" {
+		items = matchedOnly(items)
+	}
+	for _, item := range items {
+		out.Items = append(out.Items, runItem{
+			URL:      item.URL,
+			Name:     item.OriginalName,
+			Price:    item.Price,
+			ImageURL: item.ImageURL,
+			Model:    item.MatchedName,
+		})
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+func matchedOnly(items []model.MatchedItem) []model.MatchedItem {
+	var matched []model.MatchedItem
+	for _, item := range items {
+		if item.MatchedName != "" {
+			matched = append(matched, item)
+		}
+	}
+	return matched
+}
+
+func (h *Handler) listDeadJobs(w http.ResponseWriter, r *http.Request) {
+	limit, ok := queryLimit(w, r)
+	if !ok {
+		return
+	}
+
+	dead, err := h.srv.DeadJobs(limit)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	out := []deadJob{}
+	for _, d := range dead {
+		out = append(out, deadJob{
+			ID:           d.ID,
+			JobID:        d.JobID,
+			Trigger:      d.Trigger,
+			Stage:        d.Stage,
+			Attempts:     d.Attempts,
+			Error:        d.Error,
+			ItemsScraped: len(d.Checkpoint.Scraped),
+			Matches:      len(d.Checkpoint.Matched),
+			FailedAt:     d.FailedAt,
+		})
+	}
+	writeJSON(w, http.StatusOK, out)
+}
@@ -0,0 +1,116 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/drifterz13/dino-noti/command"
+	"github.com/drifterz13/dino-noti/model"
+)
+
+func (h *Handler) listSubscribers(w http.ResponseWriter, r *http.Request) {
+	subscribers, err := h.srv.Subscribers()
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	out := []subscriber{}
+	for _, s := range subscribers {
+		out = append(out, newSubscriber(s))
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+func (h *Handler) getSubscriber(w http.ResponseWriter, r *http.Request) {
+	h.writeSubscriber(w, r.PathValue("id"))
+}
+
+func (h *Handler) writeSubscriber(w http.ResponseWriter, id string) {
+	sub, err := h.srv.Subscriber(id)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	routes, err := h.srv.Routes(id)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	watchlist, err := h.srv.Watchlist(id)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	out := subscriberDetails{subscriber: newSubscriber(*sub), Routes: []route{}, Watchlist: []watchEntry{}}
+	for _, r := range routes {
+		out.Routes = append(out.Routes, route{Channel: r.Channel, Target: r.Target})
+	}
+	for _, e := range watchlist {
+		out.Watchlist = append(out.Watchlist, newWatchEntry(e))
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+// subscriberUpdateRequest changes the fields present in the body. Mute takes
+// the same values as the mute command, e.g. "2h" or "off", and preferences
+// may be partial.
+type subscriberUpdateRequest struct {
+	Active      *bool           `json:"active"`
+	Mute        *string         `json:"mute"`
+	Preferences json.RawMessage `json:"preferences"`
+}
+
+func (h *Handler) updateSubscriber(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	var req subscriberUpdateRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	sub, err := h.srv.Subscriber(id)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	update := model.SubscriberUpdate{Active: req.Active}
+	if req.Mute != nil {
+		until, err := muteUntil(*req.Mute)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		update.MutedUntil = &until
+	}
+	if req.Preferences != nil {
+		prefs := newPreferences(sub.Preferences)
+		if err := json.Unmarshal(req.Preferences, &prefs); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid preferences: %v", err))
+			return
+		}
+		modelPrefs := prefs.model()
+		update.Preferences = &modelPrefs
+	}
+
+	if _, err := h.srv.UpdateSubscriber(id, update); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	h.writeSubscriber(w, id)
+}
+
+func muteUntil(value string) (time.Time, error) {
+	if strings.EqualFold(value, "off") {
+		return time.Time{}, nil
+	}
+	d, err := command.ParseDuration(value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("mute must be a duration such as 2h or 1d, or off")
+	}
+	return time.Now().Add(d), nil
+}
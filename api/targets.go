@@ -0,0 +1,87 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/drifterz13/dino-noti/config"
+	"github.com/drifterz13/dino-noti/model"
+)
+
+type targetRequest struct {
+	Name     string `json:"name"`
+	URL      string `json:"url"`
+	MaxPages int    `json:"max_pages"`
+	Enabled  *bool  `json:"enabled"`
+}
+
+// target fills in the defaults: the configured page depth, and enabled.
+func (req targetRequest) target() model.Target {
+	t := model.Target{Name: req.Name, URL: req.URL, MaxPages: req.MaxPages, Enabled: true}
+	if t.MaxPages == 0 {
+		t.MaxPages = config.DEFAULT_MAX_PAGES
+	}
+	if req.Enabled != nil {
+		t.Enabled = *req.Enabled
+	}
+	return t
+}
+
+func (h *Handler) listTargets(w http.ResponseWriter, r *http.Request) {
+	targets, err := h.srv.Targets()
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	out := []target{}
+	for _, t := range targets {
+		out = append(out, newTarget(t))
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+func (h *Handler) addTarget(w http.ResponseWriter, r *http.Request) {
+	var req targetRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	t := req.target()
+	if err := h.srv.AddTarget(&t); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, newTarget(t))
+}
+
+func (h *Handler) updateTarget(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	var req targetRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	t := req.target()
+	t.ID = id
+	updated, err := h.srv.UpdateTarget(t)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, newTarget(*updated))
+}
+
+func (h *Handler) deleteTarget(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	if err := h.srv.DeleteTarget(id); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,217 @@
+package api
+
+import (
+	"time"
+
+	"github.com/drifterz13/dino-noti/model"
+)
+
+type watchEntry struct {
+	SourceID    string    `json:"source_id"`
+	Model       string    `json:"model"`
+	AddedBy     string    `json:"added_by,omitempty"`
+	AddedByName string    `json:"added_by_name,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func newWatchEntry(e model.WatchEntry) watchEntry {
+	return watchEntry{
+		SourceID:    e.SourceID,
+		Model:       e.Model,
+		AddedBy:     e.AddedBy,
+		AddedByName: e.AddedByName,
+		CreatedAt:   e.CreatedAt,
+	}
+}
+
+type target struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	URL       string    `json:"url"`
+	MaxPages  int       `json:"max_pages"`
+	Enabled   bool      `json:"enabled"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func newTarget(t model.Target) target {
+	return target{ID: t.ID, Name: t.Name, URL: t.URL, MaxPages: t.MaxPages, Enabled: t.Enabled, CreatedAt: t.CreatedAt}
+}
+
+type run struct {
+	ID           int64            `json:"id"`
+	Trigger      model.RunTrigger `json:"trigger"`
+	StartedAt    time.Time        `json:"started_at"`
+	FinishedAt   *time.Time       `json:"finished_at"`
+	PagesScraped int              `json:"pages_scraped"`
+	ItemsFound   int              `json:"items_found"`
+	LLMBatches   int              `json:"llm_batches"`
+	Matches      int              `json:"matches"`
+	Errors       []string         `json:"errors"`
+	Degraded     bool             `json:"degraded"`
+	Anomalies    []string         `json:"anomalies"`
+}
+
+func newRun(r model.Run) run {
+	out := run{
+		ID:           r.ID,
+		Trigger:      r.Trigger,
+		StartedAt:    r.StartedAt,
+		FinishedAt:   optionalTime(r.FinishedAt),
+		PagesScraped: r.PagesScraped,
+		ItemsFound:   r.ItemsFound,
+		LLMBatches:   r.LLMBatches,
+		Matches:      r.Matches,
+		Errors:       r.Errors,
+		Degraded:     r.Degraded(),
+		Anomalies:    r.Anomalies,
+	}
+	if out.Errors == nil {
+		out.Errors = []string{}
+	}
+	if out.Anomalies == nil {
+		out.Anomalies = []string{}
+	}
+	return out
+}
+
+type runItem struct {
+	URL      string `json:"url"`
+	Name     string `json:"name"`
+	Price    string `json:"price"`
+	ImageURL string `json:"image_url"`
+	Model    string `json:"model,omitempty"`
+}
+
+type runDetails struct {
+	run
+	Items []runItem `json:"items"`
+}
+
+type deadJob struct {
+	ID           int64            `json:"id"`
+	JobID        int64            `json:"job_id"`
+	Trigger      model.RunTrigger `json:"trigger"`
+	Stage        model.JobStage   `json:"stage"`
+	Attempts     int              `json:"attempts"`
+	Error        string           `json:"error"`
+	ItemsScraped int              `json:"items_scraped"`
+	Matches      int              `json:"matches"`
+	FailedAt     time.Time        `json:"failed_at"`
+}
+
+type listing struct {
+	AuctionID   string    `json:"auction_id"`
+	URL         string    `json:"url"`
+	Name        string    `json:"name"`
+	ImageURL    string    `json:"image_url"`
+	Model       string    `json:"model"`
+	Price       int       `json:"price"`
+	FirstSeenAt time.Time `json:"first_seen_at"`
+	LastSeenAt  time.Time `json:"last_seen_at"`
+}
+
+func newListing(l model.Listing) listing {
+	return listing{
+		AuctionID:   l.AuctionID,
+		URL:         l.URL,
+		Name:        l.Name,
+		ImageURL:    l.ImageURL,
+		Model:       l.MatchedName,
+		Price:       l.Price,
+		FirstSeenAt: l.FirstSeenAt,
+		LastSeenAt:  l.LastSeenAt,
+	}
+}
+
+type pricePoint struct {
+	AuctionID  string    `json:"auction_id"`
+	RunID      int64     `json:"run_id,omitempty"`
+	Price      int       `json:"price"`
+	ObservedAt time.Time `json:"observed_at"`
+}
+
+func newPricePoints(points []model.PricePoint) []pricePoint {
+	out := []pricePoint{}
+	for _, p := range points {
+		out = append(out, pricePoint{AuctionID: p.AuctionID, RunID: p.RunID, Price: p.Price, ObservedAt: p.ObservedAt})
+	}
+	return out
+}
+
+type priceStats struct {
+	Days   int `json:"days"`
+	Count  int `json:"count"`
+	Min    int `json:"min"`
+	Median int `json:"median"`
+	Max    int `json:"max"`
+}
+
+type preferences struct {
+	Language string `json:"language"`
+	Timezone string `json:"timezone"`
+	// Quiet hours are minutes into the day; equal values turn them off.
+	QuietStart  int              `json:"quiet_start"`
+	QuietEnd    int              `json:"quiet_end"`
+	Digest      model.DigestMode `json:"digest"`
+	MinDiscount float64          `json:"min_discount"`
+}
+
+func newPreferences(p model.Preferences) preferences {
+	return preferences{
+		Language:    p.Language,
+		Timezone:    p.Timezone,
+		QuietStart:  p.QuietStart,
+		QuietEnd:    p.QuietEnd,
+		Digest:      p.Digest,
+		MinDiscount: p.MinDiscount,
+	}
+}
+
+func (p preferences) model() model.Preferences {
+	return model.Preferences{
+		Language:    p.Language,
+		Timezone:    p.Timezone,
+		QuietStart:  p.QuietStart,
+		QuietEnd:    p.QuietEnd,
+		Digest:      p.Digest,
+		MinDiscount: p.MinDiscount,
+	}
+}
+
+type route struct {
+	Channel model.Channel `json:"channel"`
+	Target  string        `json:"target"`
+}
+
+type subscriber struct {
+	ID          string           `json:"id"`
+	Kind        model.SourceKind `json:"kind"`
+	Active      bool             `json:"active"`
+	MutedUntil  *time.Time       `json:"muted_until"`
+	Preferences preferences      `json:"preferences"`
+	CreatedAt   time.Time        `json:"created_at"`
+}
+
+func newSubscriber(s model.Subscriber) subscriber {
+	return subscriber{
+		ID:          s.ID,
+		Kind:        s.Kind,
+		Active:      s.Active,
+		MutedUntil:  optionalTime(s.MutedUntil),
+		Preferences: newPreferences(s.Preferences),
+		CreatedAt:   s.CreatedAt,
+	}
+}
+
+type subscriberDetails struct {
+	subscriber
+	Routes    []route      `json:"routes"`
+	Watchlist []watchEntry `json:"watchlist"`
+}
+
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
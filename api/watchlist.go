@@ -0,0 +1,68 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/drifterz13/dino-noti/model"
+)
+
+func (h *Handler) listWatchlist(w http.ResponseWriter, r *http.Request) {
+	source := r.URL.Query().Get("source")
+
+	var (
+		entries []model.WatchEntry
+		err     error
+	)
+	if source != "" {
+		entries, err = h.srv.Watchlist(source)
+	} else {
+		entries, err = h.srv.Watchlists()
+	}
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	out := []watchEntry{}
+	for _, e := range entries {
+		out = append(out, newWatchEntry(e))
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+type watchRequest struct {
+	SourceID string `json:"source_id"`
+	Model    string `json:"model"`
+}
+
+func (h *Handler) addWatch(w http.ResponseWriter, r *http.Request) {
+	var req watchRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	entry, added, err := h.srv.Watch(model.WatchEntry{SourceID: req.SourceID, Model: req.Model})
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	status := http.StatusCreated
+	if !added {
+		status = http.StatusOK
+	}
+	writeJSON(w, status, newWatchEntry(entry))
+}
+
+func (h *Handler) removeWatch(w http.ResponseWriter, r *http.Request) {
+	removed, err := h.srv.Unwatch(r.PathValue("source"), r.PathValue("model"))
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	if !removed {
+		writeError(w, http.StatusNotFound, "model is not on the watchlist")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
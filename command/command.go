@@ -0,0 +1,92 @@
+package command
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	Search   = "search"
+	New      = "new"
+	Watch    = "watch"
+	Unwatch  = "unwatch"
+	List     = "list"
+	Mute     = "mute"
+	Settings = "settings"
+	Help     = "help"
+	History  = "history"
+	Diff     = "diff"
+	Stats    = "stats"
+	Channels = "channels"
+	Feed     = "feed"
+)
+
+var known = []string{Search, New, Watch, Unwatch, List, Mute, Settings, Help, History, Diff, Stats, Channels, Feed}
+
+type Command struct {
+	Name string
+	Args string
+	// Unknown is set when the first word is not a known command; Name then
+	// holds that word as typed.
+	Unknown bool
+}
+
+// Parse splits a text message into a command name and its arguments.
+// Commands are case-insensitive and may be prefixed with "/".
+func Parse(text string) Command {
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return Command{Unknown: true}
+	}
+
+	name := strings.ToLower(strings.TrimPrefix(fields[0], "/"))
+	args := strings.Join(fields[1:], " ")
+
+	for _, k := range known {
+		if name == k {
+			return Command{Name: k, Args: args}
+		}
+	}
+
+	return Command{Name: fields[0], Args: args, Unknown: true}
+}
+
+// ParseDuration accepts Go durations plus a "d" suffix for days, e.g. "2h",
+// "30m" or "1d".
+func ParseDuration(s string) (time.Duration, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n <= 0 {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	return d, nil
+}
+
+// ParseQuietHours parses a period such as "22:00-07:00" into minutes after
+// midnight.
+func ParseQuietHours(s string) (int, int, error) {
+	from, to, ok := strings.Cut(strings.TrimSpace(s), "-")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid quiet hours %q", s)
+	}
+
+	var minutes [2]int
+	for i, clock := range []string{from, to} {
+		t, err := time.Parse("15:04", strings.TrimSpace(clock))
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid quiet hours %q", s)
+		}
+		minutes[i] = t.Hour()*60 + t.Minute()
+	}
+	return minutes[0], minutes[1], nil
+}
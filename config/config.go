@@ -2,7 +2,14 @@ package config
 
 import (
 	"fmt"
+	"log/slog"
+	"net/url"
 	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/drifterz13/dino-noti/i18n"
 )
 
 type Config struct {
@@ -12,14 +19,93 @@ type Config struct {
 	GeminiAPIKey      string
 	LineChannelToken  string
 	LineChannelSecret string
+	DatabasePath      string
+	ScheduleInterval  time.Duration
+	DealPercentile    float64
+	ResultFreshness   time.Duration
+	ShutdownTimeout   time.Duration
+	JobWorkers        int
+	JobMaxAttempts    int
+	CommandPrefix     string
+	Timezone          string
+	DefaultLanguage   string
+	// THBRate converts yen to baht; zero hides the baht estimates.
+	THBRate float64
+	// LandedExtraYen estimates Buyee fees plus shipping to Thailand.
+	LandedExtraYen int
+
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+	SMTPFrom     string
+
+	// AdminToken enables the admin API; it is disabled when empty.
+	AdminToken string
+	// DashboardToken is the dashboard login, defaulting to AdminToken.
+	DashboardToken string
+	// PublicURL is where the server is reachable, used to link to feeds.
+	PublicURL string
+	// PlaceholderImageURL is shown for listings without a photo. It defaults
+	// to the image served under PublicURL; without either, no image is shown.
+	PlaceholderImageURL string
+
+	LogLevel  slog.Level
+	LogFormat string
+	// RedactIDs hashes LINE user, group and room IDs in the logs.
+	RedactIDs bool
+	// OTLPEndpoint enables tracing; the exporter reads the other
+	// OTEL_EXPORTER_OTLP_* variables itself.
+	OTLPEndpoint string
+
+	// AlertLineID is the LINE user or group told when runs become degraded
+	// and when they recover; the alerts are only logged when it is empty.
+	AlertLineID string
+	// A run is degraded when its item count drops by AnomalyDropPercent from
+	// the average of the last AnomalyWindow healthy runs, when more than
+	// AnomalyMissingPercent of its items have no price or image, or when
+	// AnomalyMaxBadStatuses pages answer with a status other than 200.
+	AnomalyWindow         int
+	AnomalyDropPercent    float64
+	AnomalyMissingPercent float64
+	AnomalyMaxBadStatuses int
 }
 
 const (
 	TARGET_URL        = "https://buyee.jp/item/search/category/2084261642?sort=end&order=d&aucmin_bidorbuy_price=6000&aucmax_bidorbuy_price=30000"
 	DEFAULT_MAX_PAGES = 10
+	DEFAULT_DB_PATH   = "dino-noti.db"
+
+	DEFAULT_DEAL_PERCENTILE  = 25
+	DEFAULT_RESULT_FRESHNESS = 5 * time.Minute
+	DEFAULT_SHUTDOWN_TIMEOUT = 25 * time.Second
+	DEFAULT_JOB_WORKERS      = 1
+	DEFAULT_JOB_MAX_ATTEMPTS = 3
+	DEFAULT_COMMAND_PREFIX   = "/"
+	DEFAULT_SMTP_PORT        = 587
+	DEFAULT_TIMEZONE         = "Asia/Bangkok"
+	DEFAULT_LANGUAGE         = "th"
+	DEFAULT_LANDED_EXTRA_YEN = 3000
+	DEFAULT_LOG_FORMAT       = "json"
+	PLACEHOLDER_IMAGE_PATH   = "/images/no-image.png"
+
+	DEFAULT_ANOMALY_WINDOW          = 5
+	DEFAULT_ANOMALY_DROP_PERCENT    = 50
+	DEFAULT_ANOMALY_MISSING_PERCENT = 20
+	DEFAULT_ANOMALY_MAX_BAD_STATUS  = 3
 )
 
-func LoadConfig() (*Config, error) {
+// Requirement is a secret that only some commands need.
+type Requirement int
+
+const (
+	RequireGemini Requirement = iota
+	RequireLine
+)
+
+// LoadConfig reads the configuration from the environment, failing when a
+// required secret is missing.
+func LoadConfig(required ...Requirement) (*Config, error) {
 	cfg := &Config{
 		TargetURL: TARGET_URL,
 	}
@@ -92,20 +178,277 @@ func LoadConfig() (*Config, error) {
 
 	cfg.MyList = myList
 
-	cfg.GeminiAPIKey = os.Getenv("GEMINI_API_KEY")
-	if cfg.GeminiAPIKey == "" {
-		return nil, fmt.Errorf("GEMINI_API_KEY environment variable not set")
+	cfg.DatabasePath = os.Getenv("DB_PATH")
+	if cfg.DatabasePath == "" {
+		cfg.DatabasePath = DEFAULT_DB_PATH
 	}
 
-	cfg.LineChannelToken = os.Getenv("LINE_CHANNEL_TOKEN")
-	if cfg.LineChannelToken == "" {
-		return nil, fmt.Errorf("LINE_CHANNEL_TOKEN environment variable not set")
+	// Scheduled runs are disabled unless an interval is configured.
+	scheduleIntervalStr := os.Getenv("SCHEDULE_INTERVAL")
+	if scheduleIntervalStr != "" {
+		interval, err := time.ParseDuration(scheduleIntervalStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid SCHEDULE_INTERVAL: %w", err)
+		}
+		cfg.ScheduleInterval = interval
+	}
+
+	dealPercentileStr := os.Getenv("DEAL_PERCENTILE")
+	if dealPercentileStr == "" {
+		cfg.DealPercentile = DEFAULT_DEAL_PERCENTILE
+	} else {
+		_, err := fmt.Sscan(dealPercentileStr, &cfg.DealPercentile)
+		if err != nil || cfg.DealPercentile < 0 || cfg.DealPercentile > 100 {
+			return nil, fmt.Errorf("invalid DEAL_PERCENTILE: %q", dealPercentileStr)
+		}
+	}
+
+	// Searches within this window reuse the last run instead of scraping again.
+	resultFreshnessStr := os.Getenv("RESULT_FRESHNESS")
+	if resultFreshnessStr == "" {
+		cfg.ResultFreshness = DEFAULT_RESULT_FRESHNESS
+	} else {
+		freshness, err := time.ParseDuration(resultFreshnessStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid RESULT_FRESHNESS: %w", err)
+		}
+		cfg.ResultFreshness = freshness
+	}
+
+	// On SIGTERM, in-flight runs get this long to finish before being aborted.
+	shutdownTimeoutStr := os.Getenv("SHUTDOWN_TIMEOUT")
+	if shutdownTimeoutStr == "" {
+		cfg.ShutdownTimeout = DEFAULT_SHUTDOWN_TIMEOUT
+	} else {
+		timeout, err := time.ParseDuration(shutdownTimeoutStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid SHUTDOWN_TIMEOUT: %w", err)
+		}
+		cfg.ShutdownTimeout = timeout
+	}
+
+	// Runs are deduplicated in the queue, so one worker is usually enough.
+	jobWorkersStr := os.Getenv("JOB_WORKERS")
+	if jobWorkersStr == "" {
+		cfg.JobWorkers = DEFAULT_JOB_WORKERS
+	} else {
+		_, err := fmt.Sscan(jobWorkersStr, &cfg.JobWorkers)
+		if err != nil || cfg.JobWorkers < 1 {
+			return nil, fmt.Errorf("invalid JOB_WORKERS: %q", jobWorkersStr)
+		}
+	}
+
+	// A job stage is retried until it has failed this many times in a row.
+	jobMaxAttemptsStr := os.Getenv("JOB_MAX_ATTEMPTS")
+	if jobMaxAttemptsStr == "" {
+		cfg.JobMaxAttempts = DEFAULT_JOB_MAX_ATTEMPTS
+	} else {
+		_, err := fmt.Sscan(jobMaxAttemptsStr, &cfg.JobMaxAttempts)
+		if err != nil || cfg.JobMaxAttempts < 1 {
+			return nil, fmt.Errorf("invalid JOB_MAX_ATTEMPTS: %q", jobMaxAttemptsStr)
+		}
+	}
+
+	// In group chats the bot only answers messages with this prefix or a mention.
+	cfg.CommandPrefix = os.Getenv("COMMAND_PREFIX")
+	if cfg.CommandPrefix == "" {
+		cfg.CommandPrefix = DEFAULT_COMMAND_PREFIX
+	}
+
+	// Quiet hours of subscribers without a timezone use this one.
+	cfg.Timezone = os.Getenv("TIMEZONE")
+	if cfg.Timezone == "" {
+		cfg.Timezone = DEFAULT_TIMEZONE
+	}
+	if _, err := time.LoadLocation(cfg.Timezone); err != nil {
+		return nil, fmt.Errorf("invalid TIMEZONE: %w", err)
+	}
+
+	// Bot replies use this language until a subscriber picks another one.
+	cfg.DefaultLanguage = os.Getenv("DEFAULT_LANGUAGE")
+	if cfg.DefaultLanguage == "" {
+		cfg.DefaultLanguage = DEFAULT_LANGUAGE
+	}
+	if _, ok := i18n.ParseLang(cfg.DefaultLanguage); !ok {
+		return nil, fmt.Errorf("invalid DEFAULT_LANGUAGE %q: use th, en or ja", cfg.DefaultLanguage)
+	}
+
+	thbRateStr := os.Getenv("THB_RATE")
+	if thbRateStr != "" {
+		_, err := fmt.Sscan(thbRateStr, &cfg.THBRate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid THB_RATE: %w", err)
+		}
+	}
+
+	landedExtraStr := os.Getenv("LANDED_EXTRA_YEN")
+	if landedExtraStr == "" {
+		cfg.LandedExtraYen = DEFAULT_LANDED_EXTRA_YEN
+	} else {
+		_, err := fmt.Sscan(landedExtraStr, &cfg.LandedExtraYen)
+		if err != nil {
+			return nil, fmt.Errorf("invalid LANDED_EXTRA_YEN: %w", err)
+		}
+	}
+
+	// Email notifications are disabled unless an SMTP host is configured.
+	cfg.SMTPHost = os.Getenv("SMTP_HOST")
+	cfg.SMTPUsername = os.Getenv("SMTP_USERNAME")
+	cfg.SMTPPassword = os.Getenv("SMTP_PASSWORD")
+	cfg.SMTPFrom = os.Getenv("SMTP_FROM")
+	if cfg.SMTPFrom == "" {
+		cfg.SMTPFrom = cfg.SMTPUsername
 	}
 
+	smtpPortStr := os.Getenv("SMTP_PORT")
+	if smtpPortStr == "" {
+		cfg.SMTPPort = DEFAULT_SMTP_PORT
+	} else {
+		_, err := fmt.Sscan(smtpPortStr, &cfg.SMTPPort)
+		if err != nil {
+			return nil, fmt.Errorf("invalid SMTP_PORT: %w", err)
+		}
+	}
+
+	cfg.GeminiAPIKey = os.Getenv("GEMINI_API_KEY")
+	cfg.LineChannelToken = os.Getenv("LINE_CHANNEL_TOKEN")
 	cfg.LineChannelSecret = os.Getenv("LINE_CHANNEL_SECRET")
-	if cfg.LineChannelSecret == "" {
-		return nil, fmt.Errorf("LINE_CHANNEL_SECRET environment variable not set")
+	for _, requirement := range required {
+		if err := cfg.Check(requirement); err != nil {
+			return nil, err
+		}
+	}
+
+	cfg.AdminToken = os.Getenv("ADMIN_TOKEN")
+	cfg.DashboardToken = os.Getenv("DASHBOARD_TOKEN")
+	if cfg.DashboardToken == "" {
+		cfg.DashboardToken = cfg.AdminToken
+	}
+
+	// Feed links are only shared in chats once the server's address is known.
+	cfg.PublicURL = strings.TrimRight(os.Getenv("PUBLIC_URL"), "/")
+	if cfg.PublicURL != "" {
+		if u, err := url.Parse(cfg.PublicURL); err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+			return nil, fmt.Errorf("invalid PUBLIC_URL %q: use an http or https URL", cfg.PublicURL)
+		}
+	}
+
+	// LINE only loads images over https.
+	cfg.PlaceholderImageURL = os.Getenv("PLACEHOLDER_IMAGE_URL")
+	if cfg.PlaceholderImageURL == "" && strings.HasPrefix(cfg.PublicURL, "https://") {
+		cfg.PlaceholderImageURL = cfg.PublicURL + PLACEHOLDER_IMAGE_PATH
+	}
+	if cfg.PlaceholderImageURL != "" {
+		if u, err := url.Parse(cfg.PlaceholderImageURL); err != nil || u.Host == "" || u.Scheme != "https" {
+			return nil, fmt.Errorf("invalid PLACEHOLDER_IMAGE_URL %q: use an https URL", cfg.PlaceholderImageURL)
+		}
+	}
+
+	if logLevelStr := os.Getenv("LOG_LEVEL"); logLevelStr != "" {
+		if err := cfg.LogLevel.UnmarshalText([]byte(logLevelStr)); err != nil {
+			return nil, fmt.Errorf("invalid LOG_LEVEL %q: use debug, info, warn or error", logLevelStr)
+		}
+	}
+
+	cfg.LogFormat = os.Getenv("LOG_FORMAT")
+	if cfg.LogFormat == "" {
+		cfg.LogFormat = DEFAULT_LOG_FORMAT
+	}
+	if cfg.LogFormat != "json" && cfg.LogFormat != "text" {
+		return nil, fmt.Errorf("invalid LOG_FORMAT %q: use json or text", cfg.LogFormat)
+	}
+
+	if redactIDsStr := os.Getenv("LOG_REDACT_IDS"); redactIDsStr != "" {
+		redactIDs, err := strconv.ParseBool(redactIDsStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid LOG_REDACT_IDS: %w", err)
+		}
+		cfg.RedactIDs = redactIDs
+	}
+
+	// Spans are only recorded when there is a collector to send them to.
+	cfg.OTLPEndpoint = os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT")
+	if cfg.OTLPEndpoint == "" {
+		cfg.OTLPEndpoint = os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT")
+	}
+
+	cfg.AlertLineID = os.Getenv("ALERT_LINE_ID")
+
+	anomalyWindowStr := os.Getenv("ANOMALY_WINDOW")
+	if anomalyWindowStr == "" {
+		cfg.AnomalyWindow = DEFAULT_ANOMALY_WINDOW
+	} else {
+		_, err := fmt.Sscan(anomalyWindowStr, &cfg.AnomalyWindow)
+		if err != nil || cfg.AnomalyWindow < 1 {
+			return nil, fmt.Errorf("invalid ANOMALY_WINDOW: %q", anomalyWindowStr)
+		}
+	}
+
+	anomalyDropStr := os.Getenv("ANOMALY_DROP_PERCENT")
+	if anomalyDropStr == "" {
+		cfg.AnomalyDropPercent = DEFAULT_ANOMALY_DROP_PERCENT
+	} else {
+		_, err := fmt.Sscan(anomalyDropStr, &cfg.AnomalyDropPercent)
+		if err != nil || cfg.AnomalyDropPercent <= 0 || cfg.AnomalyDropPercent > 100 {
+			return nil, fmt.Errorf("invalid ANOMALY_DROP_PERCENT: %q", anomalyDropStr)
+		}
+	}
+
+	anomalyMissingStr := os.Getenv("ANOMALY_MISSING_PERCENT")
+	if anomalyMissingStr == "" {
+		cfg.AnomalyMissingPercent = DEFAULT_ANOMALY_MISSING_PERCENT
+	} else {
+		_, err := fmt.Sscan(anomalyMissingStr, &cfg.AnomalyMissingPercent)
+		if err != nil || cfg.AnomalyMissingPercent < 0 || cfg.AnomalyMissingPercent > 100 {
+			return nil, fmt.Errorf("invalid ANOMALY_MISSING_PERCENT: %q", anomalyMissingStr)
+		}
+	}
+
+	anomalyBadStatusStr := os.Getenv("ANOMALY_MAX_BAD_STATUS")
+	if anomalyBadStatusStr == "" {
+		cfg.AnomalyMaxBadStatuses = DEFAULT_ANOMALY_MAX_BAD_STATUS
+	} else {
+		_, err := fmt.Sscan(anomalyBadStatusStr, &cfg.AnomalyMaxBadStatuses)
+		if err != nil || cfg.AnomalyMaxBadStatuses < 1 {
+			return nil, fmt.Errorf("invalid ANOMALY_MAX_BAD_STATUS: %q", anomalyBadStatusStr)
+		}
 	}
 
 	return cfg, nil
 }
+
+// Secrets are the configured credentials, which are kept out of the logs.
+func (cfg *Config) Secrets() []string {
+	var secrets []string
+	for _, secret := range []string{
+		cfg.GeminiAPIKey,
+		cfg.LineChannelToken,
+		cfg.LineChannelSecret,
+		cfg.SMTPPassword,
+		cfg.AdminToken,
+		cfg.DashboardToken,
+	} {
+		if secret != "" {
+			secrets = append(secrets, secret)
+		}
+	}
+	return secrets
+}
+
+// Check fails when a secret the requirement needs is missing.
+func (cfg *Config) Check(requirement Requirement) error {
+	switch requirement {
+	case RequireGemini:
+		if cfg.GeminiAPIKey == "" {
+			return fmt.Errorf("GEMINI_API_KEY environment variable not set")
+		}
+	case RequireLine:
+		if cfg.LineChannelToken == "" {
+			return fmt.Errorf("LINE_CHANNEL_TOKEN environment variable not set")
+		}
+		if cfg.LineChannelSecret == "" {
+			return fmt.Errorf("LINE_CHANNEL_SECRET environment variable not set")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,212 @@
+package dashboard
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html/template"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/drifterz13/dino-noti/i18n"
+	"github.com/drifterz13/dino-noti/service"
+)
+
+const (
+	sessionCookie = "dino_noti_session"
+	sessionMaxAge = 30 * 24 * time.Hour
+)
+
+//go:embed templates/*.html
+var templateFS embed.FS
+
+// Handler serves the HTML dashboard under /dashboard/. Pages other than the
+// login page require the session cookie set by logging in with the token.
+type Handler struct {
+	srv       *service.Service
+	token     string
+	location  *time.Location
+	printer   *i18n.Printer
+	templates map[string]*template.Template
+	mux       *http.ServeMux
+}
+
+func NewHandler(srv *service.Service, token string, location *time.Location) (*Handler, error) {
+	h := &Handler{
+		srv:      srv,
+		token:    token,
+		location: location,
+		printer:  i18n.NewPrinter(i18n.English),
+		mux:      http.NewServeMux(),
+	}
+
+	templates, err := h.parseTemplates("login", "error", "matches", "models", "runs", "run", "watchlist")
+	if err != nil {
+		return nil, err
+	}
+	h.templates = templates
+
+	h.mux.HandleFunc("GET /dashboard/login", h.loginPage)
+	h.mux.HandleFunc("POST /dashboard/login", h.login)
+	h.mux.HandleFunc("POST /dashboard/logout", h.logout)
+
+	h.mux.HandleFunc("GET /dashboard/{$}", h.matchesPage)
+	h.mux.HandleFunc("GET /dashboard/models", h.modelsPage)
+	h.mux.HandleFunc("GET /dashboard/runs", h.runsPage)
+	h.mux.HandleFunc("GET /dashboard/runs/{id}", h.runPage)
+	h.mux.HandleFunc("GET /dashboard/watchlist", h.watchlistPage)
+	h.mux.HandleFunc("POST /dashboard/watchlist", h.addWatch)
+	h.mux.HandleFunc("POST /dashboard/watchlist/remove", h.removeWatch)
+
+	return h, nil
+}
+
+func (h *Handler) parseTemplates(pages ...string) (map[string]*template.Template, error) {
+	funcs := template.FuncMap{
+		"yen":      h.printer.Yen,
+		"datetime": h.formatTime,
+	}
+
+	templates := map[string]*template.Template{}
+	for _, page := range pages {
+		t, err := template.New(page).Funcs(funcs).ParseFS(templateFS, "templates/layout.html", "templates/"+page+".html")
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s template: %w", page, err)
+		}
+		templates[page] = t
+	}
+	return templates, nil
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("X-Frame-Options", "DENY")
+
+	// Forms are only accepted from the dashboard itself.
+	if r.Method == http.MethodPost && !sameOrigin(r) {
+		http.Error(w, "cross-origin request", http.StatusForbidden)
+		return
+	}
+
+	if r.URL.Path != "/dashboard/login" && !h.authenticated(r) {
+		http.Redirect(w, r, "/dashboard/login", http.StatusSeeOther)
+		return
+	}
+	h.mux.ServeHTTP(w, r)
+}
+
+// session is the cookie value proving the token was entered. It is derived
+// from the token, so changing the token logs every browser out.
+func (h *Handler) session() string {
+	mac := hmac.New(sha256.New, []byte(h.token))
+	mac.Write([]byte("dashboard session"))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (h *Handler) authenticated(r *http.Request) bool {
+	cookie, err := r.Cookie(sessionCookie)
+	return err == nil && hmac.Equal([]byte(cookie.Value), []byte(h.session()))
+}
+
+func sameOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	return err == nil && u.Host == r.Host
+}
+
+type page struct {
+	Title  string
+	Nav    string
+	Error  string
+	Notice string
+	Data   any
+}
+
+func (h *Handler) render(w http.ResponseWriter, name string, p page) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := h.templates[name].ExecuteTemplate(w, "layout", p); err != nil {
+		slog.Error("Error rendering page", "page", name, "err", err)
+	}
+}
+
+// renderError shows a page with only an error, hiding unexpected errors
+// behind a generic message.
+func (h *Handler) renderError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	message := "Something went wrong."
+	switch {
+	case errors.Is(err, service.ErrNotFound):
+		status = http.StatusNotFound
+		message = err.Error()
+	case errors.Is(err, service.ErrInvalidInput):
+		status = http.StatusBadRequest
+		message = err.Error()
+	default:
+		slog.Error("Error handling dashboard request", "err", err)
+	}
+
+	w.WriteHeader(status)
+	h.render(w, "error", page{Title: "Error", Nav: "error", Error: message})
+}
+
+// redirect sends the browser back to a page with a message to show there,
+// so reloading it does not repeat a form submission.
+func redirect(w http.ResponseWriter, r *http.Request, path, key, message string) {
+	if message != "" {
+		path += "?" + url.Values{key: {message}}.Encode()
+	}
+	http.Redirect(w, r, path, http.StatusSeeOther)
+}
+
+func (h *Handler) formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.In(h.location).Format("2006-01-02 15:04")
+}
+
+func (h *Handler) loginPage(w http.ResponseWriter, r *http.Request) {
+	if h.authenticated(r) {
+		http.Redirect(w, r, "/dashboard/", http.StatusSeeOther)
+		return
+	}
+	h.render(w, "login", page{Title: "Log in", Error: r.URL.Query().Get("error")})
+}
+
+func (h *Handler) login(w http.ResponseWriter, r *http.Request) {
+	token := r.PostFormValue("token")
+	if subtle.ConstantTimeCompare([]byte(token), []byte(h.token)) != 1 {
+		redirect(w, r, "/dashboard/login", "error", "Invalid token.")
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookie,
+		Value:    h.session(),
+		Path:     "/dashboard/",
+		MaxAge:   int(sessionMaxAge.Seconds()),
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteStrictMode,
+	})
+	http.Redirect(w, r, "/dashboard/", http.StatusSeeOther)
+}
+
+func (h *Handler) logout(w http.ResponseWriter, r *http.Request) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookie,
+		Path:     "/dashboard/",
+		MaxAge:   -1,
+		HttpOnly: true,
+		SameSite: http.SameSiteStrictMode,
+	})
+	http.Redirect(w, r, "/dashboard/login", http.StatusSeeOther)
+}
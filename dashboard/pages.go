@@ -0,0 +1,227 @@
+package dashboard
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/drifterz13/dino-noti/export"
+	"github.com/drifterz13/dino-noti/model"
+)
+
+const (
+	listingLimit = 200
+	runLimit     = 50
+	deadJobLimit = 20
+)
+
+var sparklineWindows = []int{30, 90, 365}
+
+type matchesFilter struct {
+	Model string
+	Since string
+	Until string
+}
+
+type matchesData struct {
+	Filter   matchesFilter
+	Models   []string
+	Listings []model.Listing
+	Limit    int
+}
+
+func (h *Handler) matchesPage(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	data := matchesData{
+		Filter: matchesFilter{Model: query.Get("model"), Since: query.Get("since"), Until: query.Get("until")},
+		Limit:  listingLimit,
+	}
+	p := page{Title: "Matches", Nav: "matches", Data: &data}
+
+	filter := model.ListingFilter{Model: data.Filter.Model, Limit: listingLimit}
+	var err error
+	if filter.Since, err = h.parseDate(data.Filter.Since); err != nil {
+		p.Error = fmt.Sprintf("Invalid since date: %v", err)
+	}
+	if filter.Until, err = h.parseDate(data.Filter.Until); err != nil {
+		p.Error = fmt.Sprintf("Invalid until date: %v", err)
+	}
+	// The until date is inclusive.
+	if !filter.Until.IsZero() {
+		filter.Until = filter.Until.AddDate(0, 0, 1)
+	}
+
+	if data.Models, err = h.srv.Models(); err != nil {
+		h.renderError(w, err)
+		return
+	}
+	if p.Error == "" {
+		if data.Listings, err = h.srv.Listings(filter); err != nil {
+			h.renderError(w, err)
+			return
+		}
+	}
+
+	h.render(w, "matches", p)
+}
+
+// parseDate reads a date from a date input, in the dashboard's time zone.
+func (h *Handler) parseDate(value string) (time.Time, error) {
+	return export.ParseTime(value, h.location)
+}
+
+type modelsData struct {
+	Days    int
+	Windows []int
+	Models  []modelSummary
+}
+
+type modelSummary struct {
+	Name      string
+	Count     int
+	Min       int
+	Median    int
+	Max       int
+	Latest    int
+	Sparkline *sparkline
+}
+
+func (h *Handler) modelsPage(w http.ResponseWriter, r *http.Request) {
+	data := modelsData{Days: sparklineWindows[1], Windows: sparklineWindows}
+	if days, err := strconv.Atoi(r.URL.Query().Get("days")); err == nil && slices.Contains(sparklineWindows, days) {
+		data.Days = days
+	}
+
+	models, err := h.srv.Models()
+	if err != nil {
+		h.renderError(w, err)
+		return
+	}
+
+	since := time.Now().AddDate(0, 0, -data.Days)
+	for _, name := range models {
+		_, points, err := h.srv.ModelPriceHistory(name, since)
+		if err != nil {
+			h.renderError(w, err)
+			return
+		}
+		data.Models = append(data.Models, summarizeModel(name, points, h.location))
+	}
+
+	h.render(w, "models", page{Title: "Models", Nav: "models", Data: &data})
+}
+
+type runsData struct {
+	Runs     []model.Run
+	DeadJobs []model.DeadJob
+}
+
+func (h *Handler) runsPage(w http.ResponseWriter, r *http.Request) {
+	var (
+		data runsData
+		err  error
+	)
+	if data.Runs, err = h.srv.Runs(runLimit); err != nil {
+		h.renderError(w, err)
+		return
+	}
+	if data.DeadJobs, err = h.srv.DeadJobs(deadJobLimit); err != nil {
+		h.renderError(w, err)
+		return
+	}
+
+	h.render(w, "runs", page{Title: "Runs", Nav: "runs", Data: &data})
+}
+
+type runData struct {
+	Run     *model.Run
+	Matched []model.MatchedItem
+	Scraped int
+}
+
+func (h *Handler) runPage(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	run, items, err := h.srv.RunDetails(id)
+	if err != nil {
+		h.renderError(w, err)
+		return
+	}
+
+	data := runData{Run: run, Scraped: len(items)}
+	for _, item := range items {
+		if item.MatchedName != "" {
+			data.Matched = append(data.Matched, item)
+		}
+	}
+
+	h.render(w, "run", page{Title: fmt.Sprintf("Run %d", id), Nav: "runs", Data: &data})
+}
+
+type watchlistData struct {
+	Entries     []model.WatchEntry
+	Subscribers []model.Subscriber
+	Models      []string
+}
+
+func (h *Handler) watchlistPage(w http.ResponseWriter, r *http.Request) {
+	var (
+		data watchlistData
+		err  error
+	)
+	if data.Entries, err = h.srv.Watchlists(); err != nil {
+		h.renderError(w, err)
+		return
+	}
+	if data.Subscribers, err = h.srv.Subscribers(); err != nil {
+		h.renderError(w, err)
+		return
+	}
+	if data.Models, err = h.srv.Models(); err != nil {
+		h.renderError(w, err)
+		return
+	}
+
+	h.render(w, "watchlist", page{
+		Title:  "Watchlist",
+		Nav:    "watchlist",
+		Error:  r.URL.Query().Get("error"),
+		Notice: r.URL.Query().Get("notice"),
+		Data:   &data,
+	})
+}
+
+func (h *Handler) addWatch(w http.ResponseWriter, r *http.Request) {
+	entry, added, err := h.srv.Watch(model.WatchEntry{
+		SourceID:    r.PostFormValue("source"),
+		Model:       r.PostFormValue("model"),
+		AddedByName: "dashboard",
+	})
+	switch {
+	case err != nil:
+		redirect(w, r, "/dashboard/watchlist", "error", fmt.Sprintf("Could not add the model: %v", err))
+	case !added:
+		redirect(w, r, "/dashboard/watchlist", "notice", fmt.Sprintf("%s is already on that watchlist.", entry.Model))
+	default:
+		redirect(w, r, "/dashboard/watchlist", "notice", fmt.Sprintf("Added %s.", entry.Model))
+	}
+}
+
+func (h *Handler) removeWatch(w http.ResponseWriter, r *http.Request) {
+	name := r.PostFormValue("model")
+	removed, err := h.srv.Unwatch(r.PostFormValue("source"), name)
+	switch {
+	case err != nil:
+		redirect(w, r, "/dashboard/watchlist", "error", fmt.Sprintf("Could not remove the model: %v", err))
+	case !removed:
+		redirect(w, r, "/dashboard/watchlist", "error", fmt.Sprintf("%s is not on that watchlist.", name))
+	default:
+		redirect(w, r, "/dashboard/watchlist", "notice", fmt.Sprintf("Removed %s.", name))
+	}
+}
@@ -0,0 +1,111 @@
+package dashboard
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/drifterz13/dino-noti/market"
+	"github.com/drifterz13/dino-noti/model"
+)
+
+const (
+	sparklineWidth  = 160
+	sparklineHeight = 32
+	sparklinePad    = 2
+)
+
+// sparkline is an SVG polyline of the daily median price.
+type sparkline struct {
+	Width  int
+	Height int
+	Points string
+	// LastX and LastY mark the latest day with a dot.
+	LastX float64
+	LastY float64
+}
+
+func summarizeModel(name string, points []model.PricePoint, loc *time.Location) modelSummary {
+	summary := modelSummary{Name: name, Count: len(points)}
+	if len(points) == 0 {
+		return summary
+	}
+
+	prices := make([]int, len(points))
+	for i, p := range points {
+		prices[i] = p.Price
+	}
+	sort.Ints(prices)
+	summary.Min = prices[0]
+	summary.Median = market.Median(prices)
+	summary.Max = prices[len(prices)-1]
+
+	latest := points[0]
+	for _, p := range points {
+		if p.ObservedAt.After(latest.ObservedAt) {
+			latest = p
+		}
+	}
+	summary.Latest = latest.Price
+
+	summary.Sparkline = newSparkline(dailyMedians(points, loc))
+	return summary
+}
+
+// dailyMedians returns the median price of each day with observations, in
+// date order.
+func dailyMedians(points []model.PricePoint, loc *time.Location) []int {
+	byDay := map[string][]int{}
+	for _, p := range points {
+		day := p.ObservedAt.In(loc).Format(time.DateOnly)
+		byDay[day] = append(byDay[day], p.Price)
+	}
+
+	days := make([]string, 0, len(byDay))
+	for day := range byDay {
+		days = append(days, day)
+	}
+	sort.Strings(days)
+
+	medians := make([]int, len(days))
+	for i, day := range days {
+		prices := byDay[day]
+		sort.Ints(prices)
+		medians[i] = market.Median(prices)
+	}
+	return medians
+}
+
+func newSparkline(values []int) *sparkline {
+	if len(values) == 0 {
+		return nil
+	}
+
+	low, high := values[0], values[0]
+	for _, v := range values {
+		low = min(low, v)
+		high = max(high, v)
+	}
+
+	s := &sparkline{Width: sparklineWidth, Height: sparklineHeight}
+	plotWidth := float64(sparklineWidth - 2*sparklinePad)
+	plotHeight := float64(sparklineHeight - 2*sparklinePad)
+
+	coords := make([]string, len(values))
+	for i, v := range values {
+		x := float64(sparklinePad) + plotWidth/2
+		if len(values) > 1 {
+			x = float64(sparklinePad) + plotWidth*float64(i)/float64(len(values)-1)
+		}
+		y := float64(sparklinePad) + plotHeight/2
+		if high > low {
+			y = float64(sparklinePad) + plotHeight*float64(high-v)/float64(high-low)
+		}
+		coords[i] = fmt.Sprintf("%.1f,%.1f", x, y)
+		s.LastX, s.LastY = x, y
+	}
+	s.Points = strings.Join(coords, " ")
+
+	return s
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/drifterz13/dino-noti/export"
+	"github.com/drifterz13/dino-noti/model"
+	"github.com/drifterz13/dino-noti/service"
+)
+
+func exportItems(args []string) error {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	formatName := flags.String("format", "csv", "output `format`: csv, jsonl, atom or rss")
+	modelName := flags.String("model", "", "only export listings of this `model`")
+	since := flags.String("since", "", "only export listings last seen since this `date`")
+	until := flags.String("until", "", "only export listings last seen before this `date`")
+	source := flags.String("source", "", "only export listings on the watchlist of this chat `ID`")
+	runID := flags.Int64("run", 0, "export the matched items of run `ID` instead of the listing history")
+	limit := flags.Int("limit", 0, "export at most `N` listings, newest first (default all)")
+	output := flags.String("output", "-", "`file` to write to, or - for stdout")
+	flags.Usage = usageFor(flags, "export [flags]", "Export matched listings as CSV, JSON Lines or an Atom or RSS feed.\nDates are YYYY-MM-DD in TIMEZONE or RFC 3339 timestamps.")
+	flags.Parse(args)
+
+	format, err := export.ParseFormat(*formatName)
+	if err != nil {
+		return err
+	}
+	if *limit < 0 {
+		return fmt.Errorf("invalid -limit %d", *limit)
+	}
+	if *runID != 0 && *source != "" {
+		return errors.New("-run and -source cannot be combined")
+	}
+
+	srv, close, err := openService()
+	if err != nil {
+		return err
+	}
+	defer close()
+
+	filter := model.ListingFilter{Model: *modelName, Limit: *limit}
+	if filter.Since, err = export.ParseTime(*since, srv.Location()); err != nil {
+		return fmt.Errorf("invalid -since: %w", err)
+	}
+	if filter.Until, err = export.ParseTime(*until, srv.Location()); err != nil {
+		return fmt.Errorf("invalid -until: %w", err)
+	}
+
+	var (
+		items []export.Item
+		feed  = export.Feed{Title: "dino-noti listings", SelfURL: "urn:dino-noti:export"}
+	)
+	switch {
+	case *runID != 0:
+		items, err = runItems(srv, *runID, *modelName)
+		feed.Title = fmt.Sprintf("dino-noti run %d", *runID)
+	case *source != "":
+		var listings []model.Listing
+		listings, err = srv.WatchlistListings(*source, filter)
+		items = export.FromListings(listings)
+		feed.Title = fmt.Sprintf("dino-noti watchlist of %s", *source)
+	default:
+		var listings []model.Listing
+		listings, err = srv.Listings(filter)
+		items = export.FromListings(listings)
+	}
+	if err != nil {
+		return err
+	}
+	if *modelName != "" {
+		feed.Title += " · " + *modelName
+	}
+
+	var w io.Writer = os.Stdout
+	if *output != "-" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return fmt.Errorf("failed to create output: %w", err)
+		}
+		defer f.Close()
+		w = f
+	}
+	if err := export.Write(w, format, feed, items); err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+	if *output != "-" {
+		fmt.Fprintf(os.Stderr, "Exported %d items to %s\n", len(items), *output)
+	}
+	return nil
+}
+
+// runItems returns the matched items of a run, of the model if one is given.
+func runItems(srv *service.Service, runID int64, modelName string) ([]export.Item, error) {
+	run, items, err := srv.RunDetails(runID)
+	if err != nil {
+		return nil, err
+	}
+
+	var matched []model.MatchedItem
+	for _, item := range items {
+		if item.MatchedName == "" || modelName != "" && !strings.EqualFold(item.MatchedName, modelName) {
+			continue
+		}
+		matched = append(matched, item)
+	}
+	return export.FromMatchedItems(matched, run.StartedAt), nil
+}
+
+func feedURLs(args []string) error {
+	flags := flag.NewFlagSet("feed", flag.ExitOnError)
+	source := flags.String("source", "", "LINE user, group or room `ID` whose watchlist the feeds follow")
+	rotate := flags.Bool("rotate", false, "replace the secret token, breaking the old URLs")
+	flags.Usage = usageFor(flags, "feed -source ID [flags]", "Print the secret feed URLs of a chat's watchlist. Set PUBLIC_URL for full URLs.")
+	flags.Parse(args)
+
+	if *source == "" {
+		flags.Usage()
+		return errors.New("-source is required")
+	}
+
+	srv, close, err := openService()
+	if err != nil {
+		return err
+	}
+	defer close()
+
+	token, err := srv.FeedToken(*source, *rotate)
+	if err != nil {
+		return err
+	}
+	for _, format := range export.Formats {
+		fmt.Printf("%-6s %s\n", format, srv.FeedURL(token, format))
+	}
+	return nil
+}
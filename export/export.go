@@ -0,0 +1,134 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/drifterz13/dino-noti/model"
+)
+
+type Format string
+
+const (
+	CSV   Format = "csv"
+	JSONL Format = "jsonl"
+	Atom  Format = "atom"
+	RSS   Format = "rss"
+)
+
+var Formats = []Format{CSV, JSONL, Atom, RSS}
+
+func ParseFormat(s string) (Format, error) {
+	for _, f := range Formats {
+		if strings.EqualFold(s, string(f)) {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("unknown export format %q: use csv, jsonl, atom or rss", s)
+}
+
+func (f Format) ContentType() string {
+	switch f {
+	case CSV:
+		return "text/csv; charset=utf-8"
+	case JSONL:
+		return "application/jsonl; charset=utf-8"
+	case Atom:
+		return "application/atom+xml; charset=utf-8"
+	default:
+		return "application/rss+xml; charset=utf-8"
+	}
+}
+
+// Item is an exported listing. Listings seen by a single run have the same
+// first and last seen times.
+type Item struct {
+	AuctionID   string
+	URL         string
+	Name        string
+	Model       string
+	Price       int
+	ImageURL    string
+	FirstSeenAt time.Time
+	LastSeenAt  time.Time
+}
+
+func FromListings(listings []model.Listing) []Item {
+	items := make([]Item, 0, len(listings))
+	for _, l := range listings {
+		items = append(items, Item{
+			AuctionID:   l.AuctionID,
+			URL:         l.URL,
+			Name:        l.Name,
+			Model:       l.MatchedName,
+			Price:       l.Price,
+			ImageURL:    l.ImageURL,
+			FirstSeenAt: l.FirstSeenAt,
+			LastSeenAt:  l.LastSeenAt,
+		})
+	}
+	return items
+}
+
+// FromMatchedItems exports the matched items of a run that saw them at
+// seenAt. Items with an unreadable price are exported with a zero price.
+func FromMatchedItems(matched []model.MatchedItem, seenAt time.Time) []Item {
+	items := make([]Item, 0, len(matched))
+	for _, m := range matched {
+		price, _ := model.ParsePrice(m.Price)
+		items = append(items, Item{
+			AuctionID:   m.AuctionID,
+			URL:         m.URL,
+			Name:        m.OriginalName,
+			Model:       m.MatchedName,
+			Price:       price,
+			ImageURL:    m.ImageURL,
+			FirstSeenAt: seenAt,
+			LastSeenAt:  seenAt,
+		})
+	}
+	return items
+}
+
+// Feed describes the Atom or RSS feed the items are written as. It is
+// ignored by the other formats.
+type Feed struct {
+	Title string
+	// SelfURL is where the feed is served, which also identifies it.
+	SelfURL string
+	// Link is the page the feed is about.
+	Link string
+}
+
+// Write writes the items in the given format.
+func Write(w io.Writer, format Format, feed Feed, items []Item) error {
+	switch format {
+	case CSV:
+		return writeCSV(w, items)
+	case JSONL:
+		return writeJSONL(w, items)
+	case Atom:
+		return writeAtom(w, feed, items)
+	case RSS:
+		return writeRSS(w, feed, items)
+	default:
+		return fmt.Errorf("unknown export format %q", format)
+	}
+}
+
+// ParseTime reads an RFC 3339 timestamp or a plain date, which is taken as
+// midnight in loc. An empty value is the zero time.
+func ParseTime(value string, loc *time.Location) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation(time.DateOnly, value, loc); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("%q is not a date or an RFC 3339 timestamp", value)
+}
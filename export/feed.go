@@ -0,0 +1,176 @@
+package export
+
+import (
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"time"
+
+	"github.com/drifterz13/dino-noti/i18n"
+)
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Author  atomAuthor  `xml:"author"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomEntry struct {
+	ID        string      `xml:"id"`
+	Title     string      `xml:"title"`
+	Updated   string      `xml:"updated"`
+	Published string      `xml:"published"`
+	Link      atomLink    `xml:"link"`
+	Category  atomTerm    `xml:"category"`
+	Content   atomContent `xml:"content"`
+}
+
+type atomTerm struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomContent struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+func writeAtom(w io.Writer, feed Feed, items []Item) error {
+	out := atomFeed{
+		ID:      feed.SelfURL,
+		Title:   feed.Title,
+		Updated: lastUpdated(items).Format(time.RFC3339),
+		Links:   []atomLink{{Href: feed.SelfURL, Rel: "self"}},
+		Author:  atomAuthor{Name: "dino-noti"},
+	}
+	if feed.Link != "" {
+		out.Links = append(out.Links, atomLink{Href: feed.Link})
+	}
+	for _, item := range items {
+		out.Entries = append(out.Entries, atomEntry{
+			ID:        entryID(item),
+			Title:     entryTitle(item),
+			Updated:   item.LastSeenAt.Format(time.RFC3339),
+			Published: item.FirstSeenAt.Format(time.RFC3339),
+			Link:      atomLink{Href: item.URL},
+			Category:  atomTerm{Term: item.Model},
+			Content:   atomContent{Type: "html", Body: entryHTML(item)},
+		})
+	}
+	return writeXML(w, out)
+}
+
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link"`
+	GUID        rssGUID `xml:"guid"`
+	PubDate     string  `xml:"pubDate"`
+	Category    string  `xml:"category"`
+	Description string  `xml:"description"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+func writeRSS(w io.Writer, feed Feed, items []Item) error {
+	link := feed.Link
+	if link == "" {
+		link = feed.SelfURL
+	}
+	out := rssFeed{
+		Version: "2.0",
+		Channel: rssChannel{
+			Title:         feed.Title,
+			Link:          link,
+			Description:   feed.Title,
+			LastBuildDate: lastUpdated(items).Format(time.RFC1123Z),
+		},
+	}
+	for _, item := range items {
+		out.Channel.Items = append(out.Channel.Items, rssItem{
+			Title:       entryTitle(item),
+			Link:        item.URL,
+			GUID:        rssGUID{Value: entryID(item)},
+			PubDate:     item.FirstSeenAt.Format(time.RFC1123Z),
+			Category:    item.Model,
+			Description: entryHTML(item),
+		})
+	}
+	return writeXML(w, out)
+}
+
+func writeXML(w io.Writer, v any) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// lastUpdated is when the newest item was last seen, or now for an empty feed.
+func lastUpdated(items []Item) time.Time {
+	var updated time.Time
+	for _, item := range items {
+		if item.LastSeenAt.After(updated) {
+			updated = item.LastSeenAt
+		}
+	}
+	if updated.IsZero() {
+		return time.Now()
+	}
+	return updated
+}
+
+// entryID is stable across runs, so feed readers show each listing once.
+func entryID(item Item) string {
+	return "urn:dino-noti:listing:" + item.AuctionID
+}
+
+var printer = i18n.NewPrinter(i18n.English)
+
+func entryTitle(item Item) string {
+	return fmt.Sprintf("%s · %s", item.Model, printer.Yen(item.Price))
+}
+
+func entryHTML(item Item) string {
+	body := ""
+	if item.ImageURL != "" {
+		body += fmt.Sprintf(`<p><img src="%s" alt="" width="200"></p>`, html.EscapeString(item.ImageURL))
+	}
+	body += fmt.Sprintf(`<p><a href="%s">%s</a></p><p>%s · %s</p>`,
+		html.EscapeString(item.URL), html.EscapeString(item.Name), html.EscapeString(item.Model), printer.Yen(item.Price))
+	return body
+}
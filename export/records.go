@@ -0,0 +1,62 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"time"
+)
+
+var csvHeader = []string{"auction_id", "model", "price", "name", "url", "image_url", "first_seen_at", "last_seen_at"}
+
+func writeCSV(w io.Writer, items []Item) error {
+	writer := csv.NewWriter(w)
+	writer.Write(csvHeader)
+	for _, item := range items {
+		writer.Write([]string{
+			item.AuctionID,
+			item.Model,
+			strconv.Itoa(item.Price),
+			item.Name,
+			item.URL,
+			item.ImageURL,
+			item.FirstSeenAt.Format(time.RFC3339),
+			item.LastSeenAt.Format(time.RFC3339),
+		})
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+type jsonItem struct {
+	AuctionID   string    `json:"auction_id"`
+	Model       string    `json:"model"`
+	Price       int       `json:"price"`
+	Name        string    `json:"name"`
+	URL         string    `json:"url"`
+	ImageURL    string    `json:"image_url"`
+	FirstSeenAt time.Time `json:"first_seen_at"`
+	LastSeenAt  time.Time `json:"last_seen_at"`
+}
+
+func writeJSONL(w io.Writer, items []Item) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	for _, item := range items {
+		err := encoder.Encode(jsonItem{
+			AuctionID:   item.AuctionID,
+			Model:       item.Model,
+			Price:       item.Price,
+			Name:        item.Name,
+			URL:         item.URL,
+			ImageURL:    item.ImageURL,
+			FirstSeenAt: item.FirstSeenAt,
+			LastSeenAt:  item.LastSeenAt,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package feed
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/drifterz13/dino-noti/export"
+	"github.com/drifterz13/dino-noti/model"
+	"github.com/drifterz13/dino-noti/service"
+)
+
+const (
+	defaultLimit = 100
+	maxLimit     = 1000
+)
+
+// Handler serves the watchlist feeds of each chat under /feeds/, at
+// /feeds/<token>.<format>. The secret token is the only authentication.
+type Handler struct {
+	srv *service.Service
+	mux *http.ServeMux
+}
+
+func NewHandler(srv *service.Service) *Handler {
+	h := &Handler{srv: srv, mux: http.NewServeMux()}
+	h.mux.HandleFunc("GET /feeds/{file}", h.serveFeed)
+	return h
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+func (h *Handler) serveFeed(w http.ResponseWriter, r *http.Request) {
+	token, ext, ok := strings.Cut(r.PathValue("file"), ".")
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	format, err := export.ParseFormat(ext)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	filter, err := listingFilter(r, h.srv.Location())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	_, listings, err := h.srv.FeedListings(token, filter)
+	if errors.Is(err, service.ErrNotFound) {
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		slog.Error("Error loading feed", "err", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	title := "dino-noti watchlist"
+	if filter.Model != "" {
+		title += " · " + filter.Model
+	}
+	feed := export.Feed{Title: title, SelfURL: h.srv.FeedURL(token, format)}
+
+	w.Header().Set("Content-Type", format.ContentType())
+	// Keep the token out of the logs of any linked site.
+	w.Header().Set("Referrer-Policy", "no-referrer")
+	if err := export.Write(w, format, feed, export.FromListings(listings)); err != nil {
+		slog.Error("Error writing feed", "err", err)
+	}
+}
+
+// listingFilter reads the model, since, until and limit query parameters.
+// Dates are RFC 3339 timestamps or plain dates in loc.
+func listingFilter(r *http.Request, loc *time.Location) (model.ListingFilter, error) {
+	query := r.URL.Query()
+	filter := model.ListingFilter{Model: query.Get("model"), Limit: defaultLimit}
+
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > maxLimit {
+			return filter, fmt.Errorf("limit must be between 1 and %d", maxLimit)
+		}
+		filter.Limit = limit
+	}
+
+	var err error
+	if filter.Since, err = export.ParseTime(query.Get("since"), loc); err != nil {
+		return filter, fmt.Errorf("since: %w", err)
+	}
+	if filter.Until, err = export.ParseTime(query.Get("until"), loc); err != nil {
+		return filter, fmt.Errorf("until: %w", err)
+	}
+	return filter, nil
+}
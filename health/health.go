@@ -0,0 +1,97 @@
+package health
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/drifterz13/dino-noti/model"
+	"github.com/drifterz13/dino-noti/service"
+)
+
+// Handler serves /healthz, /readyz and, when an admin token is set,
+// /diagnostics. The probes are unauthenticated; diagnostics reach out to
+// Buyee, Gemini and LINE, so they require the admin token as a bearer token.
+type Handler struct {
+	srv   *service.Service
+	token string
+	mux   *http.ServeMux
+}
+
+func NewHandler(srv *service.Service, adminToken string) *Handler {
+	h := &Handler{srv: srv, token: adminToken, mux: http.NewServeMux()}
+	h.mux.HandleFunc("GET /healthz", h.healthz)
+	h.mux.HandleFunc("GET /readyz", h.readyz)
+	if adminToken != "" {
+		h.mux.HandleFunc("GET /diagnostics", h.diagnostics)
+	}
+	return h
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+func (h *Handler) healthz(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Write([]byte("ok\n"))
+}
+
+func (h *Handler) readyz(w http.ResponseWriter, r *http.Request) {
+	writeChecks(w, h.srv.Ready(r.Context()))
+}
+
+func (h *Handler) diagnostics(w http.ResponseWriter, r *http.Request) {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(h.token)) != 1 {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="dino-noti"`)
+		http.Error(w, "invalid or missing token", http.StatusUnauthorized)
+		return
+	}
+	writeChecks(w, h.srv.Diagnostics(r.Context()))
+}
+
+type checkResponse struct {
+	Name       string `json:"name"`
+	Status     string `json:"status"`
+	Detail     string `json:"detail"`
+	DurationMS int64  `json:"duration_ms"`
+}
+
+type checksResponse struct {
+	Status string          `json:"status"`
+	Checks []checkResponse `json:"checks"`
+}
+
+// writeChecks reports the worst status of the checks overall, and responds
+// with 503 when any of them failed.
+func writeChecks(w http.ResponseWriter, checks []model.HealthCheck) {
+	out := checksResponse{Status: string(model.CheckOK), Checks: []checkResponse{}}
+	for _, check := range checks {
+		out.Checks = append(out.Checks, checkResponse{
+			Name:       check.Name,
+			Status:     string(check.Status),
+			Detail:     check.Detail,
+			DurationMS: check.Duration.Milliseconds(),
+		})
+		switch {
+		case check.Status == model.CheckFail:
+			out.Status = string(model.CheckFail)
+		case check.Status == model.CheckWarn && out.Status == string(model.CheckOK):
+			out.Status = string(model.CheckWarn)
+		}
+	}
+
+	status := http.StatusOK
+	if out.Status == string(model.CheckFail) {
+		status = http.StatusServiceUnavailable
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(out); err != nil {
+		slog.Error("Error writing health response", "err", err)
+	}
+}
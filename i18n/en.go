@@ -0,0 +1,169 @@
+package i18n
+
+var english = map[Key]string{
+	Yen:          "¥%s",
+	Baht:         "฿%s",
+	LanguageName: "English",
+
+	NoItems:        "No interesting cameras right now 🥲",
+	Searching:      "Searching… 🦖",
+	SearchFailed:   "Sorry, the search failed. Please try again later 🙏",
+	SearchDegraded: "Buyee's results couldn't be read properly just now, so there may be cameras we missed. The admins have been told, please try again later 🙏",
+	ItemsAltText:   "Cameras on the radar 🦖",
+	ItemsHeader:    "Cameras on the radar 🦖:",
+	MarketDiff:     "%s vs market",
+	MarketPrice:    "market %s",
+	ShowingRange:   "Showing %d-%d of %d",
+	MoreResults:    "More results ▶",
+	MoreCameras:    "%d more cameras 🦖",
+	ViewProduct:    "View Product",
+	TrackListing:   "👀 Track listing",
+	IgnoreListing:  "🙈 Ignore listing",
+	IgnoreModel:    "💤 Ignore model 7d",
+	OpenOnBuyee:    "🛒 Open on Buyee",
+	ResultsExpired: "These results have expired, send \"search\" for fresh ones 🦖",
+
+	BadgeNew:        "NEW",
+	BadgePriceDrop:  "PRICE DROP",
+	BadgeEndingSoon: "ENDING SOON",
+	WasPrice:        "was %s",
+	BuyItNow:        "Buy-it-now %s",
+	Bids:            "🔨 %d bids",
+	TimeLeft:        "⏱ %s left",
+	Ended:           "⏱ ended",
+	Seller:          "Seller: %s",
+	SellerRating:    "Seller rating: %s",
+	ApproxBaht:      "≈ %s",
+	LandedCost:      "landed ≈ %s",
+	Days:            "%dd",
+	Hours:           "%dh",
+	Minutes:         "%dm",
+
+	DiffHeader:       "Changes since last run 🦖 (#%d → #%d):",
+	NoChanges:        "No changes.",
+	DiffAppeared:     "🆕 Appeared (%d):",
+	DiffDisappeared:  "👋 Disappeared (%d):",
+	DiffPriceChanged: "💸 Price changed (%d):",
+	NewItemsHeader:   "New since the last run 🦖🆕:",
+	NotEnoughRuns:    "There aren't enough searches to compare yet 🦖",
+	NoNewItems:       "No new cameras since the last search 🦖",
+	StatsUsage:       "Usage: stats <model>, e.g. stats Canon IXY 200f",
+	StatsHeader:      "Price stats for %s 🦖:",
+	StatsEmpty:       "Last %d days: no prices observed",
+	StatsWindow:      "Last %d days (%d listings): min %s / median %s / max %s",
+	UnknownModel:     "I don't know the model %s 🥲",
+
+	GreetingUser:  "Hi! Dino-noti 🦖 keeps an eye on Buyee for the compact cameras you want.",
+	GreetingGroup: "Hi everyone! Dino-noti 🦖 will hunt cameras for the whole group.",
+	OnboardingUser: "Getting started:\n" +
+		"1. Add models with: watch Canon IXY 200f\n" +
+		"2. Search now with: search\n" +
+		"3. I'll push price drops and deals as I find them.",
+	OnboardingGroup: "Getting started:\n" +
+		"In this chat, start commands with %[1]s or mention me.\n" +
+		"1. Add models with: %[1]swatch Canon IXY 200f\n" +
+		"2. Search now with: %[1]ssearch\n" +
+		"3. I'll push price drops and deals to the group.",
+	QuickSearch:    "🔍 Search now",
+	QuickWatchlist: "📋 Watchlist",
+	QuickHelp:      "❓ Help",
+	Help: `Dino-noti commands 🦖
+
+search - search Buyee now
+new - listings new since the last run
+watch <model> - watch a model
+unwatch <model> - stop watching a model
+list - show your watchlist
+mute 2h - pause notifications, "mute off" to resume
+settings - language, quiet hours, digest, deal alerts
+history - changes between the last two runs
+stats <model> - observed prices
+channels - where alerts are sent, "channels add discord <url>"
+feed - feed links for your watchlist, "feed reset" for new ones
+help - show this message`,
+	UnknownCommand: "Unknown command \"%s\" 🤔\nType \"help\" to see what I can do.",
+
+	WatchUsage:      "Usage: watch <model>, e.g. watch Canon IXY 200f",
+	AlreadyWatching: "%s is already on the watchlist 👀",
+	MemberWatching:  "@%s is watching %s 👀",
+	Watching:        "Watching %s 👀",
+	UnwatchUsage:    "Usage: unwatch <model>, e.g. unwatch Canon IXY 200f",
+	NotWatching:     "%s is not on your watchlist 🤔",
+	StoppedWatching: "Stopped watching %s",
+	WatchlistEmpty:  "Your watchlist is empty. Add a model with: watch <model>",
+	WatchlistUser:   "Your watchlist 🦖:",
+	WatchlistGroup:  "This chat's watchlist 🦖:",
+	DefaultModels:   "Plus %d default models.",
+	TrackedListings: "Tracked listings 👀:",
+	Tracking:        "Tracking this listing 👀 I'll tell you when its price drops.",
+	IgnoredListing:  "Got it, I won't show this listing again 🙈",
+	IgnoringModel:   "Ignoring %s until %s 💤",
+
+	MuteUsage:             "Usage: mute <duration>, e.g. mute 2h, mute 1d or mute off",
+	Muted:                 "Notifications muted until %s 🔕",
+	Unmuted:               "Notifications resumed 🔔",
+	SettingsHeader:        "Your settings ⚙️:",
+	SettingsNotifications: "Notifications: %s",
+	NotificationsOn:       "on 🔔",
+	NotificationsMuted:    "muted until %s 🔕",
+	SettingsLanguage:      "Language: %s",
+	SettingsTimezone:      "Timezone: %s",
+	SettingsQuietHours:    "Quiet hours: %s",
+	SettingsDelivery:      "Delivery: %s",
+	SettingsWatched:       "Watched models: %d (plus %d defaults)",
+	SettingsDeals:         "Deal alerts: below the %.0fth price percentile and at least %.0f%% below market",
+	SettingsUsage: `Usage:
+settings - show your settings
+settings language th|en|ja
+settings timezone Asia/Bangkok
+settings quiet 22:00-07:00 / settings quiet off
+settings digest immediate|hourly|daily
+settings deals 20 - only alert deals at least 20% below market`,
+	UnknownTimezone:      "Unknown timezone %q, use a name like Asia/Bangkok or Asia/Tokyo 🤔",
+	UnknownLanguage:      "Unknown language %q, use th, en or ja 🤔",
+	SettingsFormTitle:    "Notification settings ⚙️",
+	SettingsFormLanguage: "Language",
+	SettingsFormDelivery: "Delivery",
+	SettingsFormQuiet:    "Quiet hours",
+	SettingsFormDeals:    "Deal alerts",
+	SettingsFormTimezone: "Timezone: settings timezone Asia/Tokyo",
+	DigestImmediate:      "Instant",
+	DigestHourly:         "Hourly",
+	DigestDaily:          "Daily",
+	Off:                  "Off",
+	AnyDeal:              "Any",
+	MinDiscount:          "at least %.0f%% below market",
+
+	ChannelsUsage: `Usage:
+channels - show where alerts are sent
+channels add <line|discord|slack|email|webhook> [target]
+channels remove <channel> [target]
+Discord, Slack and webhook targets are URLs, email targets are addresses.`,
+	ChannelsHeader:    "Alerts are sent to 📣:",
+	UnknownChannel:    "Unknown channel %q 🤔",
+	NoChannelToRemove: "No %s channel to remove 🤔",
+	ChannelNeedsURL:   "%s needs a public https webhook URL",
+	InvalidEmail:      "%q is not an email address",
+	ThisChat:          "this chat",
+
+	FeedLinks: `Your watchlist feeds 📰
+Atom: %s
+RSS: %s
+CSV: %s
+JSON Lines: %s
+Anyone with these links can read them. Send "feed reset" to replace them.`,
+	FeedUnavailable: "Feeds are not set up on this server yet 🙏",
+
+	DealsTitle:      "Deals on the radar 🦖🔥",
+	DealDetail:      "market %s, %s vs market",
+	PriceDropsTitle: "Price drops on the radar 🦖💸",
+	PriceDropDetail: "%s → %s, %s",
+	PriceDropLabel:  "price drop",
+	RelistLabel:     "cheaper relist",
+	DigestTitle:     "Digest since %s 🦖📬",
+
+	RunDegraded:       "⚠️ Run #%d (%s) is degraded:",
+	RunErrors:         "%d errors, the first one: %s",
+	RunDegradedFooter: "Further degraded runs are not alerted until one recovers.",
+	RunRecovered:      "✅ Run #%d (%s) is healthy again: %d items on %d pages.",
+}
@@ -0,0 +1,160 @@
+package i18n
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/drifterz13/dino-noti/model"
+)
+
+type Lang string
+
+const (
+	Thai     Lang = "th"
+	English  Lang = "en"
+	Japanese Lang = "ja"
+)
+
+var Langs = []Lang{Thai, English, Japanese}
+
+// ParseLang accepts a language code such as "th" or "en-US".
+func ParseLang(s string) (Lang, bool) {
+	code, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(s)), "-")
+	for _, lang := range Langs {
+		if code == string(lang) {
+			return lang, true
+		}
+	}
+	return "", false
+}
+
+var catalogs = map[Lang]map[Key]string{
+	Thai:     thai,
+	English:  english,
+	Japanese: japanese,
+}
+
+// Printer renders catalog messages, numbers and dates in one language.
+type Printer struct {
+	lang Lang
+}
+
+// NewPrinter falls back to English for unsupported languages.
+func NewPrinter(lang Lang) *Printer {
+	if _, ok := catalogs[lang]; !ok {
+		lang = English
+	}
+	return &Printer{lang: lang}
+}
+
+func (p *Printer) Lang() Lang {
+	return p.lang
+}
+
+// T formats the message for key with fmt verbs. Messages missing from a
+// catalog fall back to English.
+func (p *Printer) T(key Key, args ...any) string {
+	format, ok := catalogs[p.lang][key]
+	if !ok {
+		format, ok = english[key]
+	}
+	if !ok {
+		return string(key)
+	}
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// Number groups thousands, e.g. 12345 as "12,345".
+func (p *Printer) Number(n int) string {
+	digits := strconv.Itoa(n)
+	sign := ""
+	if n < 0 {
+		sign, digits = "-", digits[1:]
+	}
+
+	grouped := strings.Builder{}
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			grouped.WriteByte(',')
+		}
+		grouped.WriteRune(d)
+	}
+	return sign + grouped.String()
+}
+
+func (p *Printer) Yen(n int) string {
+	return p.T(Yen, p.Number(n))
+}
+
+// YenString formats a scraped price, keeping it as is when it is not a
+// number.
+func (p *Printer) YenString(price string) string {
+	yen, err := model.ParsePrice(price)
+	if err != nil {
+		return p.T(Yen, price)
+	}
+	return p.Yen(yen)
+}
+
+func (p *Printer) Baht(n int) string {
+	return p.T(Baht, p.Number(n))
+}
+
+// Duration formats a rounded duration with its two largest units, e.g.
+// "2d 3h" or "45m".
+func (p *Printer) Duration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	days := int(d / (24 * time.Hour))
+	hours := int(d % (24 * time.Hour) / time.Hour)
+	minutes := int(d % time.Hour / time.Minute)
+
+	var parts []string
+	switch {
+	case days > 0:
+		parts = append(parts, p.T(Days, days))
+		if hours > 0 {
+			parts = append(parts, p.T(Hours, hours))
+		}
+	case hours > 0:
+		parts = append(parts, p.T(Hours, hours))
+		if minutes > 0 {
+			parts = append(parts, p.T(Minutes, minutes))
+		}
+	default:
+		parts = append(parts, p.T(Minutes, minutes))
+	}
+
+	separator := " "
+	if p.lang == Japanese {
+		separator = ""
+	}
+	return strings.Join(parts, separator)
+}
+
+func (p *Printer) Percent(f float64) string {
+	return fmt.Sprintf("%+.0f%%", f)
+}
+
+var thaiMonths = []string{"ม.ค.", "ก.พ.", "มี.ค.", "เม.ย.", "พ.ค.", "มิ.ย.", "ก.ค.", "ส.ค.", "ก.ย.", "ต.ค.", "พ.ย.", "ธ.ค."}
+
+// Date formats a day without the year, e.g. "Jan 2".
+func (p *Printer) Date(t time.Time) string {
+	switch p.lang {
+	case Thai:
+		return fmt.Sprintf("%d %s", t.Day(), thaiMonths[t.Month()-1])
+	case Japanese:
+		return fmt.Sprintf("%d月%d日", t.Month(), t.Day())
+	default:
+		return t.Format("Jan 2")
+	}
+}
+
+// Time formats a day and time of day, e.g. "Jan 2 15:04".
+func (p *Printer) Time(t time.Time) string {
+	return p.Date(t) + " " + t.Format("15:04")
+}
@@ -0,0 +1,118 @@
+package i18n
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestCatalogsHaveEnglishKeys(t *testing.T) {
+	for _, lang := range Langs {
+		catalog := catalogs[lang]
+		for key := range english {
+			if _, ok := catalog[key]; !ok {
+				t.Errorf("%s: missing %q", lang, key)
+			}
+		}
+		for key := range catalog {
+			if _, ok := english[key]; !ok {
+				t.Errorf("%s: %q is not in the English catalog", lang, key)
+			}
+		}
+	}
+}
+
+// verbPattern matches a fmt verb with an optional explicit argument index.
+// The space flag is left out, so a literal "20% off" is not read as a verb.
+var verbPattern = regexp.MustCompile(`%(?:\[(\d+)\])?[-+#0]*\d*(?:\.\d+)?([a-zA-Z%])`)
+
+// verbs lists the verbs of a format with the argument each one reads, e.g.
+// "1:s", sorted and without duplicates.
+func verbs(format string) []string {
+	var out []string
+	next := 1
+	for _, match := range verbPattern.FindAllStringSubmatch(format, -1) {
+		if match[2] == "%" {
+			continue
+		}
+		arg := next
+		if match[1] != "" {
+			arg, _ = strconv.Atoi(match[1])
+		}
+		out = append(out, strconv.Itoa(arg)+":"+match[2])
+		next = arg + 1
+	}
+	slices.Sort(out)
+	return slices.Compact(out)
+}
+
+func TestCatalogVerbsMatchEnglish(t *testing.T) {
+	for _, lang := range Langs {
+		for key, format := range catalogs[lang] {
+			want := verbs(english[key])
+			if got := verbs(format); !slices.Equal(got, want) {
+				t.Errorf("%s: %q has verbs %v, English has %v", lang, key, got, want)
+			}
+		}
+	}
+}
+
+func TestVerbs(t *testing.T) {
+	tests := []struct {
+		format string
+		want   []string
+	}{
+		{"%s vs market", []string{"1:s"}},
+		{"Showing %d-%d of %d", []string{"1:d", "2:d", "3:d"}},
+		{"%[1]swatch and %[1]ssearch", []string{"1:s"}},
+		{"%[2]d then %d", []string{"2:d", "3:d"}},
+		{"at least %.0f%% below", []string{"1:f"}},
+		{"only deals 20% below market", nil},
+	}
+	for _, tt := range tests {
+		if got := verbs(tt.format); !slices.Equal(got, tt.want) {
+			t.Errorf("verbs(%q) = %v, want %v", tt.format, got, tt.want)
+		}
+	}
+}
+
+func TestYen(t *testing.T) {
+	tests := map[Lang]string{
+		Thai:     "12,345 เยน",
+		English:  "¥12,345",
+		Japanese: "12,345円",
+	}
+	for lang, want := range tests {
+		if got := NewPrinter(lang).Yen(12345); got != want {
+			t.Errorf("%s: Yen(12345) = %q, want %q", lang, got, want)
+		}
+	}
+}
+
+func TestPercent(t *testing.T) {
+	for _, lang := range Langs {
+		p := NewPrinter(lang)
+		if got := p.Percent(-12.4); got != "-12%" {
+			t.Errorf("%s: Percent(-12.4) = %q, want %q", lang, got, "-12%")
+		}
+		if got := p.Percent(5); got != "+5%" {
+			t.Errorf("%s: Percent(5) = %q, want %q", lang, got, "+5%")
+		}
+	}
+}
+
+func TestTime(t *testing.T) {
+	at := time.Date(2026, time.January, 2, 15, 4, 0, 0, time.UTC)
+	tests := map[Lang]string{
+		Thai:     "2 ม.ค. 15:04",
+		English:  "Jan 2 15:04",
+		Japanese: "1月2日 15:04",
+	}
+	for lang, want := range tests {
+		if got := NewPrinter(lang).Time(at); got != want {
+			t.Errorf("%s: Time = %q, want %q", lang, got, want)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package i18n
+
+var japanese = map[Key]string{
+	Yen:          "%s円",
+	Baht:         "%sバーツ",
+	LanguageName: "日本語",
+
+	NoItems:        "今は気になるカメラがありません 🥲",
+	Searching:      "検索中です… 🦖",
+	SearchFailed:   "検索に失敗しました。しばらくしてからもう一度お試しください 🙏",
+	SearchDegraded: "Buyeeの検索結果を正しく読み取れず、見落としたカメラがあるかもしれません。管理者に通知済みです。しばらくしてからもう一度お試しください 🙏",
+	ItemsAltText:   "レーダーに映ったカメラ 🦖",
+	ItemsHeader:    "レーダーに映ったカメラ 🦖:",
+	MarketDiff:     "相場比 %s",
+	MarketPrice:    "相場 %s",
+	ShowingRange:   "%[3]d件中 %[1]d-%[2]d件を表示",
+	MoreResults:    "もっと見る ▶",
+	MoreCameras:    "残り%d台 🦖",
+	ViewProduct:    "商品を見る",
+	TrackListing:   "👀 この出品を追跡",
+	IgnoreListing:  "🙈 この出品を非表示",
+	IgnoreModel:    "💤 機種を7日間非表示",
+	OpenOnBuyee:    "🛒 Buyeeで開く",
+	ResultsExpired: "この検索結果は期限切れです。「search」で再検索してください 🦖",
+
+	BadgeNew:        "新着",
+	BadgePriceDrop:  "値下げ",
+	BadgeEndingSoon: "まもなく終了",
+	WasPrice:        "元 %s",
+	BuyItNow:        "即決 %s",
+	Bids:            "🔨 入札%d件",
+	TimeLeft:        "⏱ 残り%s",
+	Ended:           "⏱ 終了",
+	Seller:          "出品者: %s",
+	SellerRating:    "出品者評価: %s",
+	ApproxBaht:      "≈ %s",
+	LandedCost:      "送料込み ≈ %s",
+	Days:            "%d日",
+	Hours:           "%d時間",
+	Minutes:         "%d分",
+
+	DiffHeader:       "前回の検索からの変化 🦖 (#%d → #%d):",
+	NoChanges:        "変化はありません。",
+	DiffAppeared:     "🆕 新着 (%d):",
+	DiffDisappeared:  "👋 終了 (%d):",
+	DiffPriceChanged: "💸 価格変更 (%d):",
+	NewItemsHeader:   "前回の検索以降の新着 🦖🆕:",
+	NotEnoughRuns:    "比較できる検索履歴がまだありません 🦖",
+	NoNewItems:       "前回の検索以降、新着のカメラはありません 🦖",
+	StatsUsage:       "使い方: stats <機種> 例: stats Canon IXY 200f",
+	StatsHeader:      "%s の価格統計 🦖:",
+	StatsEmpty:       "過去%d日間: 価格データなし",
+	StatsWindow:      "過去%d日間 (%d件): 最安 %s / 中央値 %s / 最高 %s",
+	UnknownModel:     "機種 %s が見つかりません 🥲",
+
+	GreetingUser:  "こんにちは! Dino-noti 🦖 がBuyeeで欲しいコンパクトカメラを見張ります。",
+	GreetingGroup: "みなさん、こんにちは! Dino-noti 🦖 がグループのためにカメラを探します。",
+	OnboardingUser: "はじめかた:\n" +
+		"1. 機種を追加: watch Canon IXY 200f\n" +
+		"2. 今すぐ検索: search\n" +
+		"3. 値下げやお買い得品を見つけたらお知らせします。",
+	OnboardingGroup: "はじめかた:\n" +
+		"このチャットではコマンドの先頭に %[1]s を付けるか、メンションしてください。\n" +
+		"1. 機種を追加: %[1]swatch Canon IXY 200f\n" +
+		"2. 今すぐ検索: %[1]ssearch\n" +
+		"3. 値下げやお買い得品をグループにお知らせします。",
+	QuickSearch:    "🔍 今すぐ検索",
+	QuickWatchlist: "📋 ウォッチリスト",
+	QuickHelp:      "❓ ヘルプ",
+	Help: `Dino-noti のコマンド 🦖
+
+search - 今すぐBuyeeを検索
+new - 前回以降の新着
+watch <機種> - 機種をウォッチ
+unwatch <機種> - ウォッチを解除
+list - ウォッチリストを表示
+mute 2h - 通知を一時停止、"mute off" で再開
+settings - 言語、通知停止時間、まとめ通知、お買い得通知
+history - 直近2回の検索の比較
+stats <機種> - 価格統計
+channels - 通知先、例: "channels add discord <url>"
+feed - ウォッチリストのフィード、"feed reset" で再発行
+help - このメッセージを表示`,
+	UnknownCommand: "「%s」というコマンドはありません 🤔\n「help」でコマンド一覧を表示します。",
+
+	WatchUsage:      "使い方: watch <機種> 例: watch Canon IXY 200f",
+	AlreadyWatching: "%s はすでにウォッチリストにあります 👀",
+	MemberWatching:  "@%s さんが %s をウォッチしています 👀",
+	Watching:        "%s をウォッチします 👀",
+	UnwatchUsage:    "使い方: unwatch <機種> 例: unwatch Canon IXY 200f",
+	NotWatching:     "%s はウォッチリストにありません 🤔",
+	StoppedWatching: "%s のウォッチを解除しました",
+	WatchlistEmpty:  "ウォッチリストは空です。追加するには: watch <機種>",
+	WatchlistUser:   "あなたのウォッチリスト 🦖:",
+	WatchlistGroup:  "このチャットのウォッチリスト 🦖:",
+	DefaultModels:   "ほかに標準の機種が%d件あります。",
+	TrackedListings: "追跡中の出品 👀:",
+	Tracking:        "この出品を追跡します 👀 値下げされたらお知らせします。",
+	IgnoredListing:  "了解です。この出品はもう表示しません 🙈",
+	IgnoringModel:   "%s を%sまで非表示にします 💤",
+
+	MuteUsage:             "使い方: mute <期間> 例: mute 2h、mute 1d、mute off",
+	Muted:                 "%sまで通知を停止します 🔕",
+	Unmuted:               "通知を再開しました 🔔",
+	SettingsHeader:        "あなたの設定 ⚙️:",
+	SettingsNotifications: "通知: %s",
+	NotificationsOn:       "オン 🔔",
+	NotificationsMuted:    "%sまで停止中 🔕",
+	SettingsLanguage:      "言語: %s",
+	SettingsTimezone:      "タイムゾーン: %s",
+	SettingsQuietHours:    "通知停止時間: %s",
+	SettingsDelivery:      "配信: %s",
+	SettingsWatched:       "ウォッチ中の機種: %d (標準 %d)",
+	SettingsDeals:         "お買い得通知: 価格が%.0fパーセンタイル未満かつ相場より%.0f%%以上安い出品",
+	SettingsUsage: `使い方:
+settings - 設定を表示
+settings language th|en|ja
+settings timezone Asia/Tokyo
+settings quiet 22:00-07:00 / settings quiet off
+settings digest immediate|hourly|daily
+settings deals 20 - 相場より20%以上安い出品のみ通知`,
+	UnknownTimezone:      "タイムゾーン %q が見つかりません。Asia/Tokyo のような名前を使ってください 🤔",
+	UnknownLanguage:      "言語 %q には対応していません。th、en、ja から選んでください 🤔",
+	SettingsFormTitle:    "通知設定 ⚙️",
+	SettingsFormLanguage: "言語",
+	SettingsFormDelivery: "配信",
+	SettingsFormQuiet:    "通知停止時間",
+	SettingsFormDeals:    "お買い得通知",
+	SettingsFormTimezone: "タイムゾーン: settings timezone Asia/Tokyo",
+	DigestImmediate:      "即時",
+	DigestHourly:         "1時間ごと",
+	DigestDaily:          "1日ごと",
+	Off:                  "オフ",
+	AnyDeal:              "すべて",
+	MinDiscount:          "相場より%.0f%%以上安い",
+
+	ChannelsUsage: `使い方:
+channels - 通知先を表示
+channels add <line|discord|slack|email|webhook> [宛先]
+channels remove <チャンネル> [宛先]
+Discord、Slack、webhook の宛先はURL、email はメールアドレスです。`,
+	ChannelsHeader:    "通知先 📣:",
+	UnknownChannel:    "チャンネル %q には対応していません 🤔",
+	NoChannelToRemove: "削除する %s チャンネルがありません 🤔",
+	ChannelNeedsURL:   "%s には公開された https の webhook URL が必要です",
+	InvalidEmail:      "%q はメールアドレスではありません",
+	ThisChat:          "このチャット",
+
+	FeedLinks: `ウォッチリストのフィード 📰
+Atom: %s
+RSS: %s
+CSV: %s
+JSON Lines: %s
+リンクを知っている人は誰でも閲覧できます。「feed reset」で新しいリンクに切り替えます。`,
+	FeedUnavailable: "このサーバーではまだフィードが設定されていません 🙏",
+
+	DealsTitle:      "レーダーに映ったお買い得品 🦖🔥",
+	DealDetail:      "相場 %s、相場比 %s",
+	PriceDropsTitle: "レーダーに映った値下げ 🦖💸",
+	PriceDropDetail: "%s → %s、%s",
+	PriceDropLabel:  "値下げ",
+	RelistLabel:     "より安く再出品",
+	DigestTitle:     "%s 以降のまとめ 🦖📬",
+
+	RunDegraded:       "⚠️ 実行 #%d (%s) に異常があります:",
+	RunErrors:         "エラー %d 件、最初のエラー: %s",
+	RunDegradedFooter: "正常に戻るまで、以降の異常な実行は通知しません。",
+	RunRecovered:      "✅ 実行 #%d (%s) は正常に戻りました: %[4]d ページで %[3]d 件",
+}
@@ -0,0 +1,147 @@
+package i18n
+
+// Key identifies a message in the catalogs.
+type Key string
+
+const (
+	Yen          Key = "yen"
+	Baht         Key = "baht"
+	LanguageName Key = "language_name"
+
+	// Search results
+	NoItems        Key = "no_items"
+	Searching      Key = "searching"
+	SearchFailed   Key = "search_failed"
+	SearchDegraded Key = "search_degraded"
+	ItemsAltText   Key = "items_alt_text"
+	ItemsHeader    Key = "items_header"
+	MarketDiff     Key = "market_diff"
+	MarketPrice    Key = "market_price"
+	ShowingRange   Key = "showing_range"
+	MoreResults    Key = "more_results"
+	MoreCameras    Key = "more_cameras"
+	ViewProduct    Key = "view_product"
+	TrackListing   Key = "track_listing"
+	IgnoreListing  Key = "ignore_listing"
+	IgnoreModel    Key = "ignore_model"
+	OpenOnBuyee    Key = "open_on_buyee"
+	ResultsExpired Key = "results_expired"
+
+	// Listing details
+	BadgeNew        Key = "badge_new"
+	BadgePriceDrop  Key = "badge_price_drop"
+	BadgeEndingSoon Key = "badge_ending_soon"
+	WasPrice        Key = "was_price"
+	BuyItNow        Key = "buy_it_now"
+	Bids            Key = "bids"
+	TimeLeft        Key = "time_left"
+	Ended           Key = "ended"
+	Seller          Key = "seller"
+	SellerRating    Key = "seller_rating"
+	ApproxBaht      Key = "approx_baht"
+	LandedCost      Key = "landed_cost"
+	Days            Key = "days"
+	Hours           Key = "hours"
+	Minutes         Key = "minutes"
+
+	// Run history and price stats
+	DiffHeader       Key = "diff_header"
+	NoChanges        Key = "no_changes"
+	DiffAppeared     Key = "diff_appeared"
+	DiffDisappeared  Key = "diff_disappeared"
+	DiffPriceChanged Key = "diff_price_changed"
+	NewItemsHeader   Key = "new_items_header"
+	NotEnoughRuns    Key = "not_enough_runs"
+	NoNewItems       Key = "no_new_items"
+	StatsUsage       Key = "stats_usage"
+	StatsHeader      Key = "stats_header"
+	StatsEmpty       Key = "stats_empty"
+	StatsWindow      Key = "stats_window"
+	UnknownModel     Key = "unknown_model"
+
+	// Onboarding and help
+	GreetingUser    Key = "greeting_user"
+	GreetingGroup   Key = "greeting_group"
+	OnboardingUser  Key = "onboarding_user"
+	OnboardingGroup Key = "onboarding_group"
+	QuickSearch     Key = "quick_search"
+	QuickWatchlist  Key = "quick_watchlist"
+	QuickHelp       Key = "quick_help"
+	Help            Key = "help"
+	UnknownCommand  Key = "unknown_command"
+
+	// Watchlist
+	WatchUsage      Key = "watch_usage"
+	AlreadyWatching Key = "already_watching"
+	MemberWatching  Key = "member_watching"
+	Watching        Key = "watching"
+	UnwatchUsage    Key = "unwatch_usage"
+	NotWatching     Key = "not_watching"
+	StoppedWatching Key = "stopped_watching"
+	WatchlistEmpty  Key = "watchlist_empty"
+	WatchlistUser   Key = "watchlist_user"
+	WatchlistGroup  Key = "watchlist_group"
+	DefaultModels   Key = "default_models"
+	TrackedListings Key = "tracked_listings"
+	Tracking        Key = "tracking"
+	IgnoredListing  Key = "ignored_listing"
+	IgnoringModel   Key = "ignoring_model"
+
+	// Muting and settings
+	MuteUsage             Key = "mute_usage"
+	Muted                 Key = "muted"
+	Unmuted               Key = "unmuted"
+	SettingsHeader        Key = "settings_header"
+	SettingsNotifications Key = "settings_notifications"
+	NotificationsOn       Key = "notifications_on"
+	NotificationsMuted    Key = "notifications_muted"
+	SettingsLanguage      Key = "settings_language"
+	SettingsTimezone      Key = "settings_timezone"
+	SettingsQuietHours    Key = "settings_quiet_hours"
+	SettingsDelivery      Key = "settings_delivery"
+	SettingsWatched       Key = "settings_watched"
+	SettingsDeals         Key = "settings_deals"
+	SettingsUsage         Key = "settings_usage"
+	UnknownTimezone       Key = "unknown_timezone"
+	UnknownLanguage       Key = "unknown_language"
+	SettingsFormTitle     Key = "settings_form_title"
+	SettingsFormLanguage  Key = "settings_form_language"
+	SettingsFormDelivery  Key = "settings_form_delivery"
+	SettingsFormQuiet     Key = "settings_form_quiet"
+	SettingsFormDeals     Key = "settings_form_deals"
+	SettingsFormTimezone  Key = "settings_form_timezone"
+	DigestImmediate       Key = "digest_immediate"
+	DigestHourly          Key = "digest_hourly"
+	DigestDaily           Key = "digest_daily"
+	Off                   Key = "off"
+	AnyDeal               Key = "any_deal"
+	MinDiscount           Key = "min_discount"
+
+	// Notification channels
+	ChannelsUsage     Key = "channels_usage"
+	ChannelsHeader    Key = "channels_header"
+	UnknownChannel    Key = "unknown_channel"
+	NoChannelToRemove Key = "no_channel_to_remove"
+	ChannelNeedsURL   Key = "channel_needs_url"
+	InvalidEmail      Key = "invalid_email"
+	ThisChat          Key = "this_chat"
+
+	// Feeds
+	FeedLinks       Key = "feed_links"
+	FeedUnavailable Key = "feed_unavailable"
+
+	// Push notifications
+	DealsTitle      Key = "deals_title"
+	DealDetail      Key = "deal_detail"
+	PriceDropsTitle Key = "price_drops_title"
+	PriceDropDetail Key = "price_drop_detail"
+	PriceDropLabel  Key = "price_drop_label"
+	RelistLabel     Key = "relist_label"
+	DigestTitle     Key = "digest_title"
+
+	// Run health alerts
+	RunDegraded       Key = "run_degraded"
+	RunErrors         Key = "run_errors"
+	RunDegradedFooter Key = "run_degraded_footer"
+	RunRecovered      Key = "run_recovered"
+)
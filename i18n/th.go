@@ -0,0 +1,169 @@
+package i18n
+
+var thai = map[Key]string{
+	Yen:          "%s เยน",
+	Baht:         "%s บาท",
+	LanguageName: "ไทย",
+
+	NoItems:        "ไม่มีกล้องที่น่าสนใจในตอนนี้เลยครับ 🥲",
+	Searching:      "กำลังค้นหาอยู่ครับ… 🦖",
+	SearchFailed:   "ขออภัยครับ ค้นหาไม่สำเร็จ ลองใหม่อีกครั้งภายหลังนะครับ 🙏",
+	SearchDegraded: "ขออภัยครับ ตอนนี้อ่านผลการค้นหาจาก Buyee ได้ไม่ครบ อาจมีกล้องที่ตกหล่นไป แจ้งแอดมินแล้ว ลองใหม่อีกครั้งภายหลังนะครับ 🙏",
+	ItemsAltText:   "กล้องที่เจอบนเรดาร์ 🦖",
+	ItemsHeader:    "กล้องที่เจอบนเรดาร์ 🦖:",
+	MarketDiff:     "%s เทียบราคาตลาด",
+	MarketPrice:    "ราคาตลาด %s",
+	ShowingRange:   "แสดง %d-%d จาก %d",
+	MoreResults:    "ดูเพิ่มเติม ▶",
+	MoreCameras:    "ยังมีกล้องอีก %d ตัว 🦖",
+	ViewProduct:    "ดูสินค้า",
+	TrackListing:   "👀 ติดตามรายการนี้",
+	IgnoreListing:  "🙈 ซ่อนรายการนี้",
+	IgnoreModel:    "💤 ซ่อนรุ่นนี้ 7 วัน",
+	OpenOnBuyee:    "🛒 เปิดใน Buyee",
+	ResultsExpired: "ผลการค้นหานี้หมดอายุแล้วครับ พิมพ์ \"search\" เพื่อค้นหาใหม่ 🦖",
+
+	BadgeNew:        "ใหม่",
+	BadgePriceDrop:  "ราคาลด",
+	BadgeEndingSoon: "ใกล้ปิด",
+	WasPrice:        "เดิม %s",
+	BuyItNow:        "ซื้อทันที %s",
+	Bids:            "🔨 %d บิด",
+	TimeLeft:        "⏱ เหลือ %s",
+	Ended:           "⏱ ปิดแล้ว",
+	Seller:          "ผู้ขาย: %s",
+	SellerRating:    "คะแนนผู้ขาย: %s",
+	ApproxBaht:      "≈ %s",
+	LandedCost:      "รวมส่งถึงไทย ≈ %s",
+	Days:            "%d วัน",
+	Hours:           "%d ชม.",
+	Minutes:         "%d นาที",
+
+	DiffHeader:       "การเปลี่ยนแปลงตั้งแต่การค้นหาครั้งก่อน 🦖 (#%d → #%d):",
+	NoChanges:        "ไม่มีการเปลี่ยนแปลงครับ",
+	DiffAppeared:     "🆕 ลงใหม่ (%d):",
+	DiffDisappeared:  "👋 หายไป (%d):",
+	DiffPriceChanged: "💸 ราคาเปลี่ยน (%d):",
+	NewItemsHeader:   "กล้องที่ลงใหม่ตั้งแต่การค้นหาครั้งก่อน 🦖🆕:",
+	NotEnoughRuns:    "ยังไม่มีประวัติการค้นหาพอให้เปรียบเทียบเลยครับ 🦖",
+	NoNewItems:       "ไม่มีกล้องใหม่ตั้งแต่การค้นหาครั้งก่อนครับ 🦖",
+	StatsUsage:       "วิธีใช้: stats <รุ่น> เช่น stats Canon IXY 200f",
+	StatsHeader:      "สถิติราคาของ %s 🦖:",
+	StatsEmpty:       "%d วันที่ผ่านมา: ยังไม่พบราคา",
+	StatsWindow:      "%d วันที่ผ่านมา (%d รายการ): ต่ำสุด %s / กลาง %s / สูงสุด %s",
+	UnknownModel:     "ไม่รู้จักรุ่น %s ครับ 🥲",
+
+	GreetingUser:  "สวัสดีครับ! Dino-noti 🦖 จะคอยหากล้องดิจิตอลคอมแพคบน Buyee ให้ครับ",
+	GreetingGroup: "สวัสดีทุกคนครับ! Dino-noti 🦖 จะคอยหากล้องให้ทั้งกลุ่มครับ",
+	OnboardingUser: "เริ่มต้นใช้งาน:\n" +
+		"1. เพิ่มรุ่นที่สนใจด้วย: watch Canon IXY 200f\n" +
+		"2. ค้นหาตอนนี้ด้วย: search\n" +
+		"3. เจอราคาลดหรือดีลดีๆ เมื่อไหร่จะแจ้งให้ทันทีครับ",
+	OnboardingGroup: "เริ่มต้นใช้งาน:\n" +
+		"ในแชทนี้ให้ขึ้นต้นคำสั่งด้วย %[1]s หรือแท็กบอทครับ\n" +
+		"1. เพิ่มรุ่นที่สนใจด้วย: %[1]swatch Canon IXY 200f\n" +
+		"2. ค้นหาตอนนี้ด้วย: %[1]ssearch\n" +
+		"3. เจอราคาลดหรือดีลดีๆ จะแจ้งในกลุ่มครับ",
+	QuickSearch:    "🔍 ค้นหาเลย",
+	QuickWatchlist: "📋 รายการที่ติดตาม",
+	QuickHelp:      "❓ วิธีใช้",
+	Help: `คำสั่งของ Dino-noti 🦖
+
+search - ค้นหากล้องตอนนี้
+new - กล้องที่เพิ่งลงใหม่
+watch <รุ่น> - ติดตามรุ่นกล้อง
+unwatch <รุ่น> - เลิกติดตามรุ่นกล้อง
+list - ดูรายการที่ติดตาม
+mute 2h - ปิดแจ้งเตือนชั่วคราว พิมพ์ "mute off" เพื่อเปิด
+settings - ภาษา ช่วงเวลางดแจ้งเตือน สรุปรวม และดีล
+history - เปรียบเทียบการค้นหาล่าสุด
+stats <รุ่น> - สถิติราคา
+channels - ช่องทางแจ้งเตือน เช่น "channels add discord <url>"
+feed - ลิงก์ฟีดของรายการที่ติดตาม พิมพ์ "feed reset" เพื่อสร้างใหม่
+help - แสดงข้อความนี้`,
+	UnknownCommand: "ไม่รู้จักคำสั่ง \"%s\" ครับ 🤔\nลองพิมพ์ help เพื่อดูคำสั่งทั้งหมด",
+
+	WatchUsage:      "วิธีใช้: watch <รุ่น> เช่น watch Canon IXY 200f",
+	AlreadyWatching: "%s อยู่ในรายการติดตามแล้วครับ 👀",
+	MemberWatching:  "@%s กำลังติดตาม %s 👀",
+	Watching:        "ติดตาม %s แล้วครับ 👀",
+	UnwatchUsage:    "วิธีใช้: unwatch <รุ่น> เช่น unwatch Canon IXY 200f",
+	NotWatching:     "%s ไม่ได้อยู่ในรายการติดตามครับ 🤔",
+	StoppedWatching: "เลิกติดตาม %s แล้วครับ",
+	WatchlistEmpty:  "ยังไม่มีรายการติดตามครับ เพิ่มด้วย: watch <รุ่น>",
+	WatchlistUser:   "รายการที่ติดตาม 🦖:",
+	WatchlistGroup:  "รายการที่แชทนี้ติดตาม 🦖:",
+	DefaultModels:   "และรุ่นเริ่มต้นอีก %d รุ่น",
+	TrackedListings: "รายการที่ติดตามราคา 👀:",
+	Tracking:        "ติดตามรายการนี้แล้วครับ 👀 ถ้าราคาลดจะแจ้งให้ทราบ",
+	IgnoredListing:  "รับทราบครับ จะไม่แสดงรายการนี้อีก 🙈",
+	IgnoringModel:   "ซ่อน %s ถึง %s 💤",
+
+	MuteUsage:             "วิธีใช้: mute <ระยะเวลา> เช่น mute 2h, mute 1d หรือ mute off",
+	Muted:                 "ปิดแจ้งเตือนถึง %s 🔕",
+	Unmuted:               "เปิดแจ้งเตือนแล้วครับ 🔔",
+	SettingsHeader:        "การตั้งค่าของคุณ ⚙️:",
+	SettingsNotifications: "การแจ้งเตือน: %s",
+	NotificationsOn:       "เปิด 🔔",
+	NotificationsMuted:    "ปิดถึง %s 🔕",
+	SettingsLanguage:      "ภาษา: %s",
+	SettingsTimezone:      "เขตเวลา: %s",
+	SettingsQuietHours:    "ช่วงงดแจ้งเตือน: %s",
+	SettingsDelivery:      "การส่ง: %s",
+	SettingsWatched:       "รุ่นที่ติดตาม: %d (และรุ่นเริ่มต้น %d)",
+	SettingsDeals:         "แจ้งดีล: ราคาต่ำกว่าเปอร์เซ็นไทล์ที่ %.0f และต่ำกว่าตลาดอย่างน้อย %.0f%%",
+	SettingsUsage: `วิธีใช้:
+settings - ดูการตั้งค่า
+settings language th|en|ja
+settings timezone Asia/Bangkok
+settings quiet 22:00-07:00 / settings quiet off
+settings digest immediate|hourly|daily
+settings deals 20 - แจ้งเฉพาะดีลที่ต่ำกว่าตลาดอย่างน้อย 20%`,
+	UnknownTimezone:      "ไม่รู้จักเขตเวลา %q ครับ ลองใช้ชื่ออย่าง Asia/Bangkok หรือ Asia/Tokyo 🤔",
+	UnknownLanguage:      "ไม่รู้จักภาษา %q ครับ ใช้ th, en หรือ ja 🤔",
+	SettingsFormTitle:    "ตั้งค่าการแจ้งเตือน ⚙️",
+	SettingsFormLanguage: "ภาษา",
+	SettingsFormDelivery: "การส่ง",
+	SettingsFormQuiet:    "ช่วงงดแจ้งเตือน",
+	SettingsFormDeals:    "แจ้งดีล",
+	SettingsFormTimezone: "เขตเวลา: settings timezone Asia/Tokyo",
+	DigestImmediate:      "ทันที",
+	DigestHourly:         "รายชั่วโมง",
+	DigestDaily:          "รายวัน",
+	Off:                  "ปิด",
+	AnyDeal:              "ทั้งหมด",
+	MinDiscount:          "ต่ำกว่าตลาดอย่างน้อย %.0f%%",
+
+	ChannelsUsage: `วิธีใช้:
+channels - ดูช่องทางแจ้งเตือน
+channels add <line|discord|slack|email|webhook> [ปลายทาง]
+channels remove <ช่องทาง> [ปลายทาง]
+Discord, Slack และ webhook ใช้ URL ส่วน email ใช้ที่อยู่อีเมล`,
+	ChannelsHeader:    "ช่องทางแจ้งเตือน 📣:",
+	UnknownChannel:    "ไม่รู้จักช่องทาง %q ครับ 🤔",
+	NoChannelToRemove: "ไม่มีช่องทาง %s ให้ลบครับ 🤔",
+	ChannelNeedsURL:   "%s ต้องใช้ webhook URL แบบ https ที่เข้าถึงได้จากภายนอกครับ",
+	InvalidEmail:      "%q ไม่ใช่ที่อยู่อีเมลครับ",
+	ThisChat:          "แชทนี้",
+
+	FeedLinks: `ฟีดของรายการที่ติดตาม 📰
+Atom: %s
+RSS: %s
+CSV: %s
+JSON Lines: %s
+ใครที่มีลิงก์เหล่านี้ก็เปิดดูได้ พิมพ์ "feed reset" เพื่อสร้างลิงก์ใหม่`,
+	FeedUnavailable: "เซิร์ฟเวอร์นี้ยังไม่ได้ตั้งค่าฟีดครับ 🙏",
+
+	DealsTitle:      "ดีลบนเรดาร์ 🦖🔥",
+	DealDetail:      "ราคาตลาด %s, %s เทียบราคาตลาด",
+	PriceDropsTitle: "ราคาลดบนเรดาร์ 🦖💸",
+	PriceDropDetail: "%s → %s, %s",
+	PriceDropLabel:  "ราคาลด",
+	RelistLabel:     "ลงขายใหม่ถูกกว่า",
+	DigestTitle:     "สรุปตั้งแต่ %s 🦖📬",
+
+	RunDegraded:       "⚠️ รอบที่ #%d (%s) ทำงานผิดปกติ:",
+	RunErrors:         "มีข้อผิดพลาด %d รายการ รายการแรก: %s",
+	RunDegradedFooter: "จะไม่แจ้งเตือนรอบที่ผิดปกติซ้ำจนกว่าจะกลับมาปกติ",
+	RunRecovered:      "✅ รอบที่ #%d (%s) กลับมาปกติแล้ว: พบ %d รายการจาก %d หน้า",
+}
@@ -0,0 +1,202 @@
+package line
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/line/line-bot-sdk-go/v8/linebot/messaging_api"
+
+	"github.com/drifterz13/dino-noti/i18n"
+	"github.com/drifterz13/dino-noti/model"
+)
+
+// LINE rejects carousels, replies and texts above these limits.
+const (
+	MaxCarouselBubbles = 12
+	MaxReplyMessages   = 5
+	MaxTextLength      = 5000
+)
+
+const PostbackMore = "more"
+
+// ItemPage is the part of a stored result set sent in one reply, starting at
+// Offset. Whatever does not fit is reachable through a "More results" postback.
+type ItemPage struct {
+	ResultSetID int64
+	Items       []model.MatchedItem
+	Offset      int
+	AsCarousel  bool
+	// PlaceholderImageURL is shown for items without a photo.
+	PlaceholderImageURL string
+}
+
+func ComposeItemPage(p *i18n.Printer, page ItemPage) []messaging_api.MessageInterface {
+	if page.AsCarousel {
+		return composeCarousels(p, page)
+	}
+	return composeTextList(p, page)
+}
+
+func composeCarousels(p *i18n.Printer, page ItemPage) []messaging_api.MessageInterface {
+	remaining := page.Items[page.Offset:]
+
+	// Keep the last slot for the "More results" bubble when items do not fit.
+	capacity := MaxReplyMessages * MaxCarouselBubbles
+	hasMore := len(remaining) > capacity
+	if hasMore {
+		capacity--
+		remaining = remaining[:capacity]
+	}
+
+	var bubbles []*messaging_api.FlexBubble
+	for _, item := range remaining {
+		bubbles = append(bubbles, BuildFlexBubbleContainer(p, item, page.PlaceholderImageURL))
+	}
+	if hasMore {
+		next := page.Offset + capacity
+		bubbles = append(bubbles, buildMoreBubble(p, moreAction(p, page, next), len(page.Items)-next))
+	}
+
+	var messages []messaging_api.MessageInterface
+	for start := 0; start < len(bubbles); start += MaxCarouselBubbles {
+		end := min(start+MaxCarouselBubbles, len(bubbles))
+		carousel := BuildCarouselFlexMessage(bubbles[start:end])
+		carousel.AltText = p.T(i18n.ItemsAltText)
+		messages = append(messages, carousel)
+	}
+	return messages
+}
+
+func composeTextList(p *i18n.Printer, page ItemPage) []messaging_api.MessageInterface {
+	var texts []string
+	current := strings.Builder{}
+	current.WriteString(p.T(i18n.ItemsHeader) + "\n")
+
+	// Leave room for the "showing x-y of z" line of the last message.
+	const footerReserve = 50
+
+	next := page.Offset
+	for ; next < len(page.Items); next++ {
+		item := page.Items[next]
+		line := itemLine(p, next+1, item)
+
+		if textLength(current.String())+textLength(line) > MaxTextLength-footerReserve {
+			if len(texts)+1 == MaxReplyMessages {
+				break
+			}
+			texts = append(texts, current.String())
+			current.Reset()
+		}
+		current.WriteString(line)
+	}
+
+	hasMore := next < len(page.Items)
+	if hasMore {
+		current.WriteString("\n" + p.T(i18n.ShowingRange, page.Offset+1, next, len(page.Items)))
+	}
+	texts = append(texts, current.String())
+
+	messages := make([]messaging_api.MessageInterface, len(texts))
+	for i, text := range texts {
+		message := messaging_api.TextMessage{Text: strings.TrimRight(text, "\n")}
+		if hasMore && i == len(texts)-1 {
+			message.QuickReply = &messaging_api.QuickReply{
+				Items: []messaging_api.QuickReplyItem{quickReplyItem(moreAction(p, page, next))},
+			}
+		}
+		messages[i] = message
+	}
+	return messages
+}
+
+func moreAction(p *i18n.Printer, page ItemPage, next int) *messaging_api.PostbackAction {
+	params := url.Values{
+		"set":    {strconv.FormatInt(page.ResultSetID, 10)},
+		"offset": {strconv.Itoa(next)},
+	}
+	if page.AsCarousel {
+		params.Set("view", "carousel")
+	}
+	return &messaging_api.PostbackAction{
+		Label:       p.T(i18n.MoreResults),
+		Data:        EncodePostback(PostbackMore, params),
+		DisplayText: p.T(i18n.MoreResults),
+	}
+}
+
+func buildMoreBubble(p *i18n.Printer, action *messaging_api.PostbackAction, remaining int) *messaging_api.FlexBubble {
+	return &messaging_api.FlexBubble{
+		Body: &messaging_api.FlexBox{
+			Layout:  messaging_api.FlexBoxLAYOUT_VERTICAL,
+			Spacing: "md",
+			Contents: []messaging_api.FlexComponentInterface{
+				&messaging_api.FlexText{
+					Text:   p.T(i18n.MoreCameras, remaining),
+					Size:   string(messaging_api.FlexTextFontSize_LG),
+					Weight: messaging_api.FlexTextWEIGHT_BOLD,
+					Wrap:   true,
+				},
+				&messaging_api.FlexButton{
+					Style:  messaging_api.FlexButtonSTYLE_PRIMARY,
+					Action: action,
+				},
+			},
+		},
+	}
+}
+
+// splitText breaks text on line boundaries into at most MaxReplyMessages
+// chunks that fit the text limit, truncating whatever is left over.
+func splitText(text string) []string {
+	var chunks []string
+	current := strings.Builder{}
+
+	for _, line := range strings.SplitAfter(text, "\n") {
+		for textLength(line) > MaxTextLength {
+			head, tail := cutText(line, MaxTextLength)
+			if current.Len() > 0 {
+				chunks = append(chunks, current.String())
+				current.Reset()
+			}
+			chunks = append(chunks, head)
+			line = tail
+		}
+		if textLength(current.String())+textLength(line) > MaxTextLength {
+			chunks = append(chunks, current.String())
+			current.Reset()
+		}
+		current.WriteString(line)
+	}
+	if current.Len() > 0 || len(chunks) == 0 {
+		chunks = append(chunks, current.String())
+	}
+
+	if len(chunks) > MaxReplyMessages {
+		chunks = chunks[:MaxReplyMessages]
+		last, _ := cutText(chunks[MaxReplyMessages-1], MaxTextLength-1)
+		chunks[MaxReplyMessages-1] = last + "…"
+	}
+	return chunks
+}
+
+// textLength counts UTF-16 code units, which is how LINE measures text.
+func textLength(s string) int {
+	n := 0
+	for _, r := range s {
+		n += utf16.RuneLen(r)
+	}
+	return n
+}
+
+func cutText(s string, limit int) (string, string) {
+	n := 0
+	for i, r := range s {
+		n += utf16.RuneLen(r)
+		if n > limit {
+			return s[:i], s[i:]
+		}
+	}
+	return s, ""
+}
@@ -1,6 +1,7 @@
 package line
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
@@ -11,6 +12,7 @@ import (
 	"github.com/line/line-bot-sdk-go/v8/linebot/webhook"
 
 	"github.com/drifterz13/dino-noti/config"
+	"github.com/drifterz13/dino-noti/i18n"
 	"github.com/drifterz13/dino-noti/model"
 )
 
@@ -19,9 +21,13 @@ type LineBotClient struct {
 	Cfg *config.Config
 }
 
-func NewLineBotClient(cfg *config.Config) (*LineBotClient, error) {
+// NewLineBotClient creates a client whose API calls are bound to ctx. The
+// context is stored on the underlying API client, so a client must not be
+// shared by work with different lifetimes.
+func NewLineBotClient(ctx context.Context, cfg *config.Config) (*LineBotClient, error) {
 	bot, err := messaging_api.NewMessagingApiAPI(
 		cfg.LineChannelToken,
+		messaging_api.WithHTTPClient(httpClient),
 	)
 
 	if err != nil {
@@ -29,11 +35,21 @@ func NewLineBotClient(cfg *config.Config) (*LineBotClient, error) {
 	}
 
 	return &LineBotClient{
-		Bot: bot,
+		Bot: bot.WithContext(ctx),
 		Cfg: cfg,
 	}, nil
 }
 
+// BotName returns the display name of the bot, which doubles as a check that
+// the channel access token is valid.
+func (c *LineBotClient) BotName() (string, error) {
+	info, err := c.Bot.GetBotInfo()
+	if err != nil {
+		return "", fmt.Errorf("Failed to get bot info: %v", err)
+	}
+	return info.DisplayName, nil
+}
+
 func (c *LineBotClient) ParseEvents(req *http.Request) ([]webhook.EventInterface, error) {
 	if req.Method != http.MethodPost {
 		return nil, errors.New("Invalid request method")
@@ -51,55 +67,19 @@ func (c *LineBotClient) ParseEvents(req *http.Request) ([]webhook.EventInterface
 	return cb.Events, nil
 }
 
-func (c *LineBotClient) HandleSendMessage(events []webhook.EventInterface, items []model.MatchedItem) error {
-	for _, event := range events {
-
-		switch e := event.(type) {
-		case webhook.MessageEvent:
-			switch message := e.Message.(type) {
-			case webhook.StickerMessageContent:
-				if len(items) == 0 {
-					c.SendMessage(e.ReplyToken, "ไม่มีกล้องที่น่าสนใจในตอนนี้เลยครับ 🥲")
-					return nil
-				}
-
-				var flexBubbles []*messaging_api.FlexBubble
-				for _, item := range items {
-					flexBubble := BuildFlexBubbleContainer(item.MatchedName, item.Price, item.ImageURL, item.URL)
-					flexBubbles = append(flexBubbles, flexBubble)
-				}
-				carousel := BuildCarouselFlexMessage(flexBubbles)
-				if err := c.SendFlexMessages(e.ReplyToken, *carousel); err != nil {
-					return err
-				}
-			case webhook.TextMessageContent:
-				if len(items) == 0 {
-					c.SendMessage(e.ReplyToken, "ไม่มีกล้องที่น่าสนใจในตอนนี้เลยครับ 🥲")
-					return nil
-				}
-
-				replyMessage := generateMessage(items)
-				c.SendMessage(e.ReplyToken, replyMessage)
-			default:
-				return fmt.Errorf("Unsupported message type: %T\n", message)
-			}
-		default:
-			return fmt.Errorf("Unsupported event type: %T\n", e)
-		}
+// ItemPageMessages renders as many matched items as fit in one reply.
+func ItemPageMessages(p *i18n.Printer, page ItemPage) []messaging_api.MessageInterface {
+	if len(page.Items) == 0 {
+		return TextMessages(p.T(i18n.NoItems))
 	}
-
-	return nil
+	return ComposeItemPage(p, page)
 }
 
 func (c *LineBotClient) SendMessage(replyToken string, replyMessage string) error {
 	if _, err := c.Bot.ReplyMessage(
 		&messaging_api.ReplyMessageRequest{
 			ReplyToken: replyToken,
-			Messages: []messaging_api.MessageInterface{
-				messaging_api.TextMessage{
-					Text: replyMessage,
-				},
-			},
+			Messages:   TextMessages(replyMessage),
 		},
 	); err != nil {
 		return fmt.Errorf("Failed to reply message: %v", err)
@@ -108,16 +88,11 @@ func (c *LineBotClient) SendMessage(replyToken string, replyMessage string) erro
 	return nil
 }
 
-func (c *LineBotClient) SendFlexMessages(replyToken string, flexMessage messaging_api.FlexMessage) error {
+func (c *LineBotClient) SendMessages(replyToken string, messages ...messaging_api.MessageInterface) error {
 	if _, err := c.Bot.ReplyMessage(
 		&messaging_api.ReplyMessageRequest{
 			ReplyToken: replyToken,
-			Messages: []messaging_api.MessageInterface{
-				&messaging_api.FlexMessage{
-					AltText:  "Cameras on radar 🦖",
-					Contents: flexMessage.Contents,
-				},
-			},
+			Messages:   messages,
 		},
 	); err != nil {
 		return fmt.Errorf("Failed to reply message: %v", err)
@@ -126,11 +101,108 @@ func (c *LineBotClient) SendFlexMessages(replyToken string, flexMessage messagin
 	return nil
 }
 
-func generateMessage(matchedItems []model.MatchedItem) string {
+func (c *LineBotClient) PushMessages(to string, messages ...messaging_api.MessageInterface) error {
+	if _, err := c.Bot.PushMessage(
+		&messaging_api.PushMessageRequest{
+			To:       to,
+			Messages: messages,
+		},
+		"",
+	); err != nil {
+		return fmt.Errorf("Failed to push message to %s: %v", to, err)
+	}
+
+	return nil
+}
+
+// ShowLoadingAnimation shows the typing indicator in a one-on-one chat until
+// the next message arrives, for at most a minute.
+func (c *LineBotClient) ShowLoadingAnimation(userID string) error {
+	if _, err := c.Bot.ShowLoadingAnimation(
+		&messaging_api.ShowLoadingAnimationRequest{
+			ChatId:         userID,
+			LoadingSeconds: 60,
+		},
+	); err != nil {
+		return fmt.Errorf("Failed to show loading animation: %v", err)
+	}
+
+	return nil
+}
+
+// TextMessages splits text that is too long for a single LINE message.
+func TextMessages(text string) []messaging_api.MessageInterface {
+	var messages []messaging_api.MessageInterface
+	for _, chunk := range splitText(text) {
+		messages = append(messages, messaging_api.TextMessage{Text: chunk})
+	}
+	return messages
+}
+
+// marketLabel describes how an item compares to its market price, e.g.
+// "-23% vs market", or returns an empty string without a market price.
+func marketLabel(p *i18n.Printer, item model.MatchedItem, prefix string) string {
+	if item.MarketPrice == 0 {
+		return ""
+	}
+	return prefix + p.T(i18n.MarketDiff, p.Percent(item.MarketDiffPercent))
+}
+
+func itemLine(p *i18n.Printer, number int, item model.MatchedItem) string {
+	return fmt.Sprintf("%d. (%s%s) %s - %s\n", number, p.YenString(item.Price), marketLabel(p, item, ", "), item.MatchedName, item.URL)
+}
+
+func GenerateDiffMessage(p *i18n.Printer, diff *model.RunDiff) string {
 	msg := strings.Builder{}
-	msg.WriteString(fmt.Sprintf("Cameras on the radar 🦖:\n"))
-	for idx, item := range matchedItems {
-		msg.WriteString(fmt.Sprintf("%d. (%s yen) %s - %s\n", idx+1, item.Price, item.MatchedName, item.URL))
+	msg.WriteString(p.T(i18n.DiffHeader, diff.Previous.ID, diff.Current.ID) + "\n")
+
+	if len(diff.Appeared) == 0 && len(diff.Disappeared) == 0 && len(diff.PriceChanged) == 0 {
+		msg.WriteString(p.T(i18n.NoChanges) + "\n")
+		return msg.String()
+	}
+
+	if len(diff.Appeared) > 0 {
+		msg.WriteString("\n" + p.T(i18n.DiffAppeared, len(diff.Appeared)) + "\n")
+		for idx, item := range diff.Appeared {
+			msg.WriteString(fmt.Sprintf("%d. (%s) %s - %s\n", idx+1, p.YenString(item.Price), item.MatchedName, item.URL))
+		}
+	}
+
+	if len(diff.Disappeared) > 0 {
+		msg.WriteString("\n" + p.T(i18n.DiffDisappeared, len(diff.Disappeared)) + "\n")
+		for idx, item := range diff.Disappeared {
+			msg.WriteString(fmt.Sprintf("%d. (%s) %s - %s\n", idx+1, p.YenString(item.Price), item.MatchedName, item.URL))
+		}
+	}
+
+	if len(diff.PriceChanged) > 0 {
+		msg.WriteString("\n" + p.T(i18n.DiffPriceChanged, len(diff.PriceChanged)) + "\n")
+		for idx, change := range diff.PriceChanged {
+			msg.WriteString(fmt.Sprintf("%d. (%s → %s) %s - %s\n", idx+1, p.YenString(change.OldPrice), p.YenString(change.Item.Price), change.Item.MatchedName, change.Item.URL))
+		}
+	}
+
+	return msg.String()
+}
+
+func GenerateNewItemsMessage(p *i18n.Printer, items []model.MatchedItem) string {
+	msg := strings.Builder{}
+	msg.WriteString(p.T(i18n.NewItemsHeader) + "\n")
+	for idx, item := range items {
+		msg.WriteString(itemLine(p, idx+1, item))
+	}
+	return msg.String()
+}
+
+func GenerateStatsMessage(p *i18n.Printer, stats []model.PriceStats) string {
+	msg := strings.Builder{}
+	msg.WriteString(p.T(i18n.StatsHeader, stats[0].Model) + "\n")
+	for _, s := range stats {
+		if s.Count == 0 {
+			msg.WriteString(p.T(i18n.StatsEmpty, s.Days) + "\n")
+			continue
+		}
+		msg.WriteString(p.T(i18n.StatsWindow, s.Days, s.Count, p.Yen(s.Min), p.Yen(s.Median), p.Yen(s.Max)) + "\n")
 	}
 	return msg.String()
 }
@@ -0,0 +1,53 @@
+package line
+
+import (
+	"fmt"
+
+	"github.com/line/line-bot-sdk-go/v8/linebot/messaging_api"
+
+	"github.com/drifterz13/dino-noti/command"
+	"github.com/drifterz13/dino-noti/i18n"
+	"github.com/drifterz13/dino-noti/model"
+)
+
+// BuildWelcomeMessages greets a new follower or group and offers the first
+// steps as quick replies.
+func BuildWelcomeMessages(p *i18n.Printer, kind model.SourceKind, commandPrefix string) []messaging_api.MessageInterface {
+	greeting := p.T(i18n.GreetingUser)
+	onboarding := p.T(i18n.OnboardingUser)
+	if kind != model.SourceUser {
+		greeting = p.T(i18n.GreetingGroup)
+		onboarding = p.T(i18n.OnboardingGroup, commandPrefix)
+	}
+
+	// Offer the other languages up front, since the default may not fit.
+	var languages []messaging_api.QuickReplyItem
+	for _, lang := range i18n.Langs {
+		if lang == p.Lang() {
+			continue
+		}
+		label := i18n.NewPrinter(lang).T(i18n.LanguageName)
+		languages = append(languages, quickReplyItem(CommandPostbackAction("🌐 "+label, command.Settings, fmt.Sprintf("language %s", lang))))
+	}
+
+	return []messaging_api.MessageInterface{
+		messaging_api.TextMessage{Text: greeting},
+		messaging_api.TextMessage{
+			Text: onboarding,
+			QuickReply: &messaging_api.QuickReply{
+				Items: append([]messaging_api.QuickReplyItem{
+					quickReplyItem(CommandPostbackAction(p.T(i18n.QuickSearch), command.Search, "")),
+					quickReplyItem(CommandPostbackAction(p.T(i18n.QuickWatchlist), command.List, "")),
+					quickReplyItem(CommandPostbackAction(p.T(i18n.QuickHelp), command.Help, "")),
+				}, languages...),
+			},
+		},
+	}
+}
+
+func quickReplyItem(action messaging_api.ActionInterface) messaging_api.QuickReplyItem {
+	return messaging_api.QuickReplyItem{
+		Type:   "action",
+		Action: action,
+	}
+}
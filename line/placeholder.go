@@ -0,0 +1,17 @@
+package line
+
+import (
+	_ "embed"
+	"net/http"
+)
+
+//go:embed no-image.png
+var placeholderImage []byte
+
+// ServePlaceholderImage serves the image shown for listings without a photo,
+// at config.PLACEHOLDER_IMAGE_PATH.
+func ServePlaceholderImage(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("Cache-Control", "public, max-age=86400")
+	w.Write(placeholderImage)
+}
@@ -0,0 +1,48 @@
+package line
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/line/line-bot-sdk-go/v8/linebot/messaging_api"
+)
+
+// Postback data is URL-encoded with the handler name in the "action" key,
+// e.g. "action=command&name=search".
+const (
+	PostbackActionKey = "action"
+	PostbackCommand   = "command"
+	PostbackTrack     = "track"
+	PostbackIgnore    = "ignore"
+	PostbackSnooze    = "snooze"
+)
+
+func EncodePostback(action string, params url.Values) string {
+	data := url.Values{}
+	for k, v := range params {
+		data[k] = v
+	}
+	data.Set(PostbackActionKey, action)
+	return data.Encode()
+}
+
+func ParsePostback(data string) (string, url.Values, error) {
+	params, err := url.ParseQuery(data)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid postback data %q: %w", data, err)
+	}
+	return params.Get(PostbackActionKey), params, nil
+}
+
+// CommandPostbackAction runs a bot command as if the user had typed it.
+func CommandPostbackAction(label, name, args string) *messaging_api.PostbackAction {
+	params := url.Values{"name": {name}}
+	if args != "" {
+		params.Set("args", args)
+	}
+	return &messaging_api.PostbackAction{
+		Label:       label,
+		Data:        EncodePostback(PostbackCommand, params),
+		DisplayText: label,
+	}
+}
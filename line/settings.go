@@ -0,0 +1,116 @@
+package line
+
+import (
+	"fmt"
+
+	"github.com/line/line-bot-sdk-go/v8/linebot/messaging_api"
+
+	"github.com/drifterz13/dino-noti/command"
+	"github.com/drifterz13/dino-noti/i18n"
+	"github.com/drifterz13/dino-noti/model"
+)
+
+// BuildSettingsForm renders the notification preferences as a flex message
+// whose buttons run the matching settings commands.
+func BuildSettingsForm(p *i18n.Printer, prefs model.Preferences, timezone string) *messaging_api.FlexMessage {
+	quiet := p.T(i18n.Off)
+	if prefs.HasQuietHours() {
+		quiet = FormatQuietHours(prefs)
+	}
+
+	section := func(title, value string, options ...[2]string) messaging_api.FlexComponentInterface {
+		var buttons []messaging_api.FlexComponentInterface
+		for _, option := range options {
+			buttons = append(buttons, &messaging_api.FlexButton{
+				Style:  messaging_api.FlexButtonSTYLE_SECONDARY,
+				Height: messaging_api.FlexButtonHEIGHT_SM,
+				Action: CommandPostbackAction(option[0], command.Settings, option[1]),
+			})
+		}
+		return &messaging_api.FlexBox{
+			Layout:  messaging_api.FlexBoxLAYOUT_VERTICAL,
+			Spacing: "sm",
+			Contents: []messaging_api.FlexComponentInterface{
+				&messaging_api.FlexText{
+					Text:   title,
+					Weight: messaging_api.FlexTextWEIGHT_BOLD,
+					Size:   string(messaging_api.FlexTextFontSize_SM),
+				},
+				&messaging_api.FlexText{
+					Text:  value,
+					Size:  string(messaging_api.FlexTextFontSize_SM),
+					Color: "#666666",
+					Wrap:  true,
+				},
+				&messaging_api.FlexBox{
+					Layout:   messaging_api.FlexBoxLAYOUT_HORIZONTAL,
+					Spacing:  "sm",
+					Contents: buttons,
+				},
+			},
+		}
+	}
+
+	var languages [][2]string
+	for _, lang := range i18n.Langs {
+		languages = append(languages, [2]string{i18n.NewPrinter(lang).T(i18n.LanguageName), "language " + string(lang)})
+	}
+
+	bubble := &messaging_api.FlexBubble{
+		Body: &messaging_api.FlexBox{
+			Layout:  messaging_api.FlexBoxLAYOUT_VERTICAL,
+			Spacing: "lg",
+			Contents: []messaging_api.FlexComponentInterface{
+				&messaging_api.FlexText{
+					Text:   p.T(i18n.SettingsFormTitle),
+					Weight: messaging_api.FlexTextWEIGHT_BOLD,
+					Size:   string(messaging_api.FlexTextFontSize_LG),
+				},
+				section(p.T(i18n.SettingsFormLanguage), p.T(i18n.LanguageName), languages...),
+				section(p.T(i18n.SettingsFormDelivery), DigestLabel(p, prefs.Digest),
+					[2]string{p.T(i18n.DigestImmediate), "digest immediate"},
+					[2]string{p.T(i18n.DigestHourly), "digest hourly"},
+					[2]string{p.T(i18n.DigestDaily), "digest daily"},
+				),
+				section(p.T(i18n.SettingsFormQuiet), fmt.Sprintf("%s (%s)", quiet, timezone),
+					[2]string{"22-07", "quiet 22:00-07:00"},
+					[2]string{"00-08", "quiet 00:00-08:00"},
+					[2]string{p.T(i18n.Off), "quiet off"},
+				),
+				section(p.T(i18n.SettingsFormDeals), p.T(i18n.MinDiscount, prefs.MinDiscount),
+					[2]string{p.T(i18n.AnyDeal), "deals 0"},
+					[2]string{"-20%", "deals 20"},
+					[2]string{"-40%", "deals 40"},
+				),
+				&messaging_api.FlexText{
+					Text:  p.T(i18n.SettingsFormTimezone),
+					Size:  string(messaging_api.FlexTextFontSize_XS),
+					Color: "#999999",
+					Wrap:  true,
+				},
+			},
+		},
+	}
+
+	return &messaging_api.FlexMessage{
+		AltText:  p.T(i18n.SettingsFormTitle),
+		Contents: bubble,
+	}
+}
+
+// DigestLabel names a digest mode in the printer's language.
+func DigestLabel(p *i18n.Printer, mode model.DigestMode) string {
+	switch mode {
+	case model.DigestHourly:
+		return p.T(i18n.DigestHourly)
+	case model.DigestDaily:
+		return p.T(i18n.DigestDaily)
+	default:
+		return p.T(i18n.DigestImmediate)
+	}
+}
+
+// FormatQuietHours renders quiet hours as "22:00-07:00".
+func FormatQuietHours(prefs model.Preferences) string {
+	return fmt.Sprintf("%02d:%02d-%02d:%02d", prefs.QuietStart/60, prefs.QuietStart%60, prefs.QuietEnd/60, prefs.QuietEnd%60)
+}
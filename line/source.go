@@ -0,0 +1,128 @@
+package line
+
+import (
+	"fmt"
+	"sort"
+	"unicode/utf16"
+
+	"github.com/line/line-bot-sdk-go/v8/linebot/webhook"
+
+	"github.com/drifterz13/dino-noti/model"
+)
+
+// Source is the chat an event came from. For groups and rooms, ID is the
+// chat and UserID the member who triggered the event, when LINE shares it.
+type Source struct {
+	ID     string
+	Kind   model.SourceKind
+	UserID string
+}
+
+func EventSource(event webhook.EventInterface) (Source, bool) {
+	var source webhook.SourceInterface
+	switch e := event.(type) {
+	case webhook.MessageEvent:
+		source = e.Source
+	case webhook.FollowEvent:
+		source = e.Source
+	case webhook.UnfollowEvent:
+		source = e.Source
+	case webhook.JoinEvent:
+		source = e.Source
+	case webhook.LeaveEvent:
+		source = e.Source
+	case webhook.PostbackEvent:
+		source = e.Source
+	}
+
+	switch s := source.(type) {
+	case webhook.UserSource:
+		return Source{ID: s.UserId, Kind: model.SourceUser, UserID: s.UserId}, s.UserId != ""
+	case webhook.GroupSource:
+		return Source{ID: s.GroupId, Kind: model.SourceGroup, UserID: s.UserId}, true
+	case webhook.RoomSource:
+		return Source{ID: s.RoomId, Kind: model.SourceRoom, UserID: s.UserId}, true
+	}
+	return Source{}, false
+}
+
+// EventID returns the ID LINE gives a webhook event, which stays the same
+// when the event is redelivered.
+func EventID(event webhook.EventInterface) string {
+	switch e := event.(type) {
+	case webhook.MessageEvent:
+		return e.WebhookEventId
+	case webhook.FollowEvent:
+		return e.WebhookEventId
+	case webhook.UnfollowEvent:
+		return e.WebhookEventId
+	case webhook.JoinEvent:
+		return e.WebhookEventId
+	case webhook.LeaveEvent:
+		return e.WebhookEventId
+	case webhook.PostbackEvent:
+		return e.WebhookEventId
+	}
+	return ""
+}
+
+// DisplayName looks up the LINE name of the user behind an event, using the
+// member profile endpoints for groups and rooms.
+func (c *LineBotClient) DisplayName(source Source) (string, error) {
+	if source.UserID == "" {
+		return "", fmt.Errorf("source %s has no user", source.ID)
+	}
+
+	switch source.Kind {
+	case model.SourceGroup:
+		profile, err := c.Bot.GetGroupMemberProfile(source.ID, source.UserID)
+		if err != nil {
+			return "", fmt.Errorf("Failed to get group member profile: %v", err)
+		}
+		return profile.DisplayName, nil
+	case model.SourceRoom:
+		profile, err := c.Bot.GetRoomMemberProfile(source.ID, source.UserID)
+		if err != nil {
+			return "", fmt.Errorf("Failed to get room member profile: %v", err)
+		}
+		return profile.DisplayName, nil
+	default:
+		profile, err := c.Bot.GetProfile(source.UserID)
+		if err != nil {
+			return "", fmt.Errorf("Failed to get profile: %v", err)
+		}
+		return profile.DisplayName, nil
+	}
+}
+
+// StripBotMention removes mentions of the bot from a text message and reports
+// whether there were any.
+func StripBotMention(message webhook.TextMessageContent) (string, bool) {
+	if message.Mention == nil {
+		return message.Text, false
+	}
+
+	var mentions []webhook.UserMentionee
+	for _, mentionee := range message.Mention.Mentionees {
+		if m, ok := mentionee.(webhook.UserMentionee); ok && m.IsSelf {
+			mentions = append(mentions, m)
+		}
+	}
+	if len(mentions) == 0 {
+		return message.Text, false
+	}
+
+	// Cut from the back so earlier positions stay valid. Positions are
+	// counted in UTF-16 code units.
+	sort.Slice(mentions, func(i, j int) bool { return mentions[i].Index > mentions[j].Index })
+	text := utf16.Encode([]rune(message.Text))
+	for _, m := range mentions {
+		start, end := int(m.Index), int(m.Index+m.Length)
+		if start < 0 || end > len(text) || start > end {
+			continue
+		}
+		text = append(text[:start:start], text[end:]...)
+	}
+
+	return string(utf16.Decode(text)), true
+}
@@ -0,0 +1,71 @@
+package line
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+
+	"github.com/drifterz13/dino-noti/metrics"
+	"github.com/drifterz13/dino-noti/tracing"
+)
+
+// httpClient records the result and latency of every Messaging API call and
+// traces it as a child of the span in the client's context.
+var httpClient = &http.Client{Transport: instrumentedTransport{next: http.DefaultTransport}}
+
+type instrumentedTransport struct {
+	next http.RoundTripper
+}
+
+func (t instrumentedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	operation := apiOperation(req.URL.Path)
+	ctx, span := tracing.Start(req.Context(), "line."+operation,
+		attribute.String("http.request.method", req.Method),
+	)
+	defer span.End()
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req.WithContext(ctx))
+	metrics.LineDuration.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+
+	code := "error"
+	switch {
+	case err != nil:
+		tracing.Fail(span, err)
+	default:
+		code = strconv.Itoa(resp.StatusCode)
+		span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+		if resp.StatusCode >= 400 {
+			span.SetStatus(codes.Error, resp.Status)
+		}
+	}
+	metrics.LineRequests.WithLabelValues(operation, code).Inc()
+	return resp, err
+}
+
+// apiOperation names the Messaging API endpoint of a request path, leaving
+// out the user, group and room IDs in it.
+func apiOperation(path string) string {
+	switch {
+	case strings.HasSuffix(path, "/message/reply"):
+		return "reply"
+	case strings.HasSuffix(path, "/message/push"):
+		return "push"
+	case strings.HasSuffix(path, "/chat/loading/start"):
+		return "loading"
+	case path == "/v2/bot/info":
+		return "bot_info"
+	case strings.HasPrefix(path, "/v2/bot/profile/"):
+		return "profile"
+	case strings.HasPrefix(path, "/v2/bot/group/") && strings.Contains(path, "/member/"):
+		return "group_member_profile"
+	case strings.HasPrefix(path, "/v2/bot/room/") && strings.Contains(path, "/member/"):
+		return "room_member_profile"
+	default:
+		return "other"
+	}
+}
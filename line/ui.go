@@ -2,8 +2,14 @@ package line
 
 import (
 	"fmt"
+	"net/url"
+	"strings"
+	"time"
 
 	"github.com/line/line-bot-sdk-go/v8/linebot/messaging_api"
+
+	"github.com/drifterz13/dino-noti/i18n"
+	"github.com/drifterz13/dino-noti/model"
 )
 
 func BuildCarouselFlexMessage(bubbles []*messaging_api.FlexBubble) *messaging_api.FlexMessage {
@@ -21,38 +27,230 @@ func BuildCarouselFlexMessage(bubbles []*messaging_api.FlexBubble) *messaging_ap
 	}
 }
 
-func BuildFlexBubbleContainer(title, price, imageURL, productURL string) *messaging_api.FlexBubble {
+const endingSoonWithin = 3 * time.Hour
+
+// BuildFlexBubbleContainer renders an item as a bubble. Listings without a
+// photo show the placeholder image, or no image when there is none, since
+// LINE rejects bubbles with an empty image URL.
+func BuildFlexBubbleContainer(p *i18n.Printer, item model.MatchedItem, placeholderImageURL string) *messaging_api.FlexBubble {
+	now := time.Now()
+
+	var contents []messaging_api.FlexComponentInterface
+	if badges := buildBadges(p, item, now); badges != nil {
+		contents = append(contents, badges)
+	}
+
+	contents = append(contents, &messaging_api.FlexText{
+		Text: item.MatchedName,
+		Size: string(messaging_api.FlexTextFontSize_MD),
+		Wrap: true,
+	})
+	if item.OriginalName != "" && item.OriginalName != item.MatchedName {
+		contents = append(contents, &messaging_api.FlexText{
+			Text:     item.OriginalName,
+			Size:     string(messaging_api.FlexTextFontSize_XS),
+			Color:    "#999999",
+			Wrap:     true,
+			MaxLines: 2,
+		})
+	}
+
+	contents = append(contents, &messaging_api.FlexText{
+		Text:   p.YenString(item.Price),
+		Size:   string(messaging_api.FlexTextFontSize_LG),
+		Weight: messaging_api.FlexTextWEIGHT_BOLD,
+		Wrap:   true,
+	})
+	if item.PreviousPrice > 0 {
+		contents = append(contents, &messaging_api.FlexText{
+			Text:       p.T(i18n.WasPrice, p.Yen(item.PreviousPrice)),
+			Size:       string(messaging_api.FlexTextFontSize_XS),
+			Color:      "#999999",
+			Decoration: messaging_api.FlexTextDECORATION_LINE_THROUGH,
+		})
+	}
+	if item.PriceTHB > 0 {
+		contents = append(contents, detailText(fmt.Sprintf("%s · %s",
+			p.T(i18n.ApproxBaht, p.Baht(item.PriceTHB)),
+			p.T(i18n.LandedCost, p.Baht(item.LandedTHB)),
+		)))
+	}
+	if item.Auction.BuyoutPrice != "" {
+		contents = append(contents, detailText(p.T(i18n.BuyItNow, p.YenString(item.Auction.BuyoutPrice))))
+	}
+
+	if item.MarketPrice > 0 {
+		color := "#d32f2f"
+		if item.MarketDiffPercent < 0 {
+			color = "#2e7d32"
+		}
+		contents = append(contents, &messaging_api.FlexText{
+			Text:  fmt.Sprintf("%s (%s)", marketLabel(p, item, ""), p.T(i18n.MarketPrice, p.Yen(item.MarketPrice))),
+			Size:  string(messaging_api.FlexTextFontSize_SM),
+			Color: color,
+			Wrap:  true,
+		})
+	}
+
+	var auction []string
+	if item.Auction.Bids > 0 {
+		auction = append(auction, p.T(i18n.Bids, item.Auction.Bids))
+	}
+	if !item.Auction.EndsAt.IsZero() {
+		if left := item.Auction.EndsAt.Sub(now); left > 0 {
+			auction = append(auction, p.T(i18n.TimeLeft, p.Duration(left)))
+		} else {
+			auction = append(auction, p.T(i18n.Ended))
+		}
+	}
+	if len(auction) > 0 {
+		contents = append(contents, detailText(strings.Join(auction, " · ")))
+	}
+	var seller []string
+	if item.Auction.Seller != "" {
+		seller = append(seller, p.T(i18n.Seller, item.Auction.Seller))
+	}
+	if item.Auction.SellerRating != "" {
+		seller = append(seller, p.T(i18n.SellerRating, item.Auction.SellerRating))
+	}
+	if len(seller) > 0 {
+		contents = append(contents, detailText(strings.Join(seller, " · ")))
+	}
+
+	imageURL := item.ImageURL
+	if imageURL == "" {
+		imageURL = placeholderImageURL
+	}
+
 	bubble := &messaging_api.FlexBubble{
-		Hero: &messaging_api.FlexImage{
+		Body: &messaging_api.FlexBox{
+			Layout:   messaging_api.FlexBoxLAYOUT_VERTICAL,
+			Spacing:  "md",
+			Contents: contents,
+		},
+		Footer: buildItemFooter(p, item),
+		Styles: &messaging_api.FlexBubbleStyles{
+			Body: &messaging_api.FlexBlockStyle{
+				BackgroundColor: "#ffffff",
+			},
+		},
+	}
+	if imageURL != "" {
+		bubble.Hero = &messaging_api.FlexImage{
 			Url:         imageURL,
 			Size:        "full",
 			AspectRatio: "1:1",
 			AspectMode:  "cover",
-			Action:      messaging_api.UriAction{Uri: productURL, Label: "View Product"},
+			Action:      messaging_api.UriAction{Uri: item.URL, Label: p.T(i18n.ViewProduct)},
+		}
+	}
+
+	return bubble
+}
+
+// buildBadges highlights new listings, price drops and auctions about to
+// end. It returns nil when none apply.
+func buildBadges(p *i18n.Printer, item model.MatchedItem, now time.Time) *messaging_api.FlexBox {
+	var badges []messaging_api.FlexComponentInterface
+	if item.IsNew {
+		badges = append(badges, badge(p.T(i18n.BadgeNew), "#1976d2"))
+	}
+	if item.PreviousPrice > 0 {
+		badges = append(badges, badge(p.T(i18n.BadgePriceDrop), "#2e7d32"))
+	}
+	if left := item.Auction.EndsAt.Sub(now); !item.Auction.EndsAt.IsZero() && left > 0 && left <= endingSoonWithin {
+		badges = append(badges, badge(p.T(i18n.BadgeEndingSoon), "#d32f2f"))
+	}
+	if len(badges) == 0 {
+		return nil
+	}
+
+	return &messaging_api.FlexBox{
+		Layout:   messaging_api.FlexBoxLAYOUT_HORIZONTAL,
+		Spacing:  "sm",
+		Contents: badges,
+	}
+}
+
+func badge(label, color string) *messaging_api.FlexBox {
+	return &messaging_api.FlexBox{
+		Layout:          messaging_api.FlexBoxLAYOUT_VERTICAL,
+		BackgroundColor: color,
+		CornerRadius:    "md",
+		PaddingAll:      "xs",
+		PaddingStart:    "sm",
+		PaddingEnd:      "sm",
+		Contents: []messaging_api.FlexComponentInterface{
+			&messaging_api.FlexText{
+				Text:   label,
+				Size:   string(messaging_api.FlexTextFontSize_XXS),
+				Color:  "#ffffff",
+				Weight: messaging_api.FlexTextWEIGHT_BOLD,
+			},
 		},
-		Body: &messaging_api.FlexBox{
-			Layout:  messaging_api.FlexBoxLAYOUT_VERTICAL,
-			Spacing: "md",
-			Contents: []messaging_api.FlexComponentInterface{
-				&messaging_api.FlexText{
-					Text: title,
-					Size: string(messaging_api.FlexTextFontSize_MD),
-					Wrap: true,
+	}
+}
+
+func detailText(text string) *messaging_api.FlexText {
+	return &messaging_api.FlexText{
+		Text:  text,
+		Size:  string(messaging_api.FlexTextFontSize_XS),
+		Color: "#666666",
+		Wrap:  true,
+	}
+}
+
+// buildItemFooter adds the buttons that let a user act on a listing. Their
+// postbacks are handled by the service, which persists the decision. Listings
+// without an auction ID cannot be tracked or ignored, so they only get the
+// model and link buttons.
+func buildItemFooter(p *i18n.Printer, item model.MatchedItem) *messaging_api.FlexBox {
+	listing := url.Values{"id": {item.AuctionID}}
+	matchedModel := url.Values{"model": {item.MatchedName}}
+
+	var buttons []messaging_api.FlexComponentInterface
+	if item.AuctionID != "" {
+		buttons = append(buttons,
+			&messaging_api.FlexButton{
+				Style:  messaging_api.FlexButtonSTYLE_PRIMARY,
+				Height: messaging_api.FlexButtonHEIGHT_SM,
+				Action: &messaging_api.PostbackAction{
+					Label: p.T(i18n.TrackListing),
+					Data:  EncodePostback(PostbackTrack, listing),
 				},
-				&messaging_api.FlexText{
-					Text:   fmt.Sprintf("%s JP¥", price),
-					Size:   string(messaging_api.FlexTextFontSize_LG),
-					Weight: messaging_api.FlexTextWEIGHT_BOLD,
-					Wrap:   true,
+			},
+			&messaging_api.FlexButton{
+				Style:  messaging_api.FlexButtonSTYLE_SECONDARY,
+				Height: messaging_api.FlexButtonHEIGHT_SM,
+				Action: &messaging_api.PostbackAction{
+					Label: p.T(i18n.IgnoreListing),
+					Data:  EncodePostback(PostbackIgnore, listing),
 				},
 			},
+		)
+	}
+	buttons = append(buttons,
+		&messaging_api.FlexButton{
+			Style:  messaging_api.FlexButtonSTYLE_SECONDARY,
+			Height: messaging_api.FlexButtonHEIGHT_SM,
+			Action: &messaging_api.PostbackAction{
+				Label: p.T(i18n.IgnoreModel),
+				Data:  EncodePostback(PostbackSnooze, matchedModel),
+			},
 		},
-		Styles: &messaging_api.FlexBubbleStyles{
-			Body: &messaging_api.FlexBlockStyle{
-				BackgroundColor: "#ffffff",
+		&messaging_api.FlexButton{
+			Style:  messaging_api.FlexButtonSTYLE_LINK,
+			Height: messaging_api.FlexButtonHEIGHT_SM,
+			Action: &messaging_api.UriAction{
+				Label: p.T(i18n.OpenOnBuyee),
+				Uri:   item.URL,
 			},
 		},
-	}
+	)
 
-	return bubble
+	return &messaging_api.FlexBox{
+		Layout:   messaging_api.FlexBoxLAYOUT_VERTICAL,
+		Spacing:  "sm",
+		Contents: buttons,
+	}
 }
@@ -3,20 +3,27 @@ package llm
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"strings"
 	"time"
 
 	"github.com/drifterz13/dino-noti/matcher"
+	"github.com/drifterz13/dino-noti/metrics"
 	"github.com/drifterz13/dino-noti/model"
+	"github.com/drifterz13/dino-noti/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/genai"
 )
 
+const geminiModel = "gemini-2.0-flash"
+
 type LLMClient struct {
 	client *genai.Client
 }
 
-func NewLLMClient(apiKey string) (*LLMClient, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+func NewLLMClient(ctx context.Context, apiKey string) (*LLMClient, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
 	client, err := genai.NewClient(ctx, &genai.ClientConfig{
@@ -30,30 +37,59 @@ func NewLLMClient(apiKey string) (*LLMClient, error) {
 	return &LLMClient{client: client}, nil
 }
 
-func (c *LLMClient) CheckMatches(itemDescriptions []string, searchTerms []string) ([]model.MatchedItem, error) {
+// Ping checks that the model used for matching is reachable with the API key.
+func (c *LLMClient) Ping(ctx context.Context) error {
+	if _, err := c.client.Models.Get(ctx, geminiModel, nil); err != nil {
+		return fmt.Errorf("failed to get model %s: %w", geminiModel, err)
+	}
+	return nil
+}
+
+// Model is the name of the model used for matching.
+func (c *LLMClient) Model() string {
+	return geminiModel
+}
+
+func (c *LLMClient) CheckMatches(ctx context.Context, itemDescriptions []string, searchTerms []string) ([]model.MatchedItem, error) {
 	var matchedItems []model.MatchedItem
 
 	prompt := buildProductNames(itemDescriptions)
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
-	resp, err := c.client.Models.GenerateContent(ctx, "gemini-2.0-flash", genai.Text(prompt), nil)
+	generateCtx, span := tracing.Start(ctx, "llm.generate_content", attribute.String("gen_ai.request.model", geminiModel))
+	start := time.Now()
+	resp, err := c.client.Models.GenerateContent(generateCtx, geminiModel, genai.Text(prompt), nil)
+	metrics.LLMDuration.Observe(time.Since(start).Seconds())
 	if err != nil {
+		tracing.End(span, err)
+		metrics.LLMCalls.WithLabelValues("error").Inc()
 		return matchedItems, fmt.Errorf("failed to generate content from LLM: %w", err)
 	}
+	recordUsage(span, resp.UsageMetadata)
+	span.End()
 
-	if len(resp.Candidates) == 0 || len(resp.Candidates[0].Content.Parts) == 0 {
-		fmt.Println("LLM returned no candidates or parts.")
+	if len(resp.Candidates) == 0 || resp.Candidates[0].Content == nil || len(resp.Candidates[0].Content.Parts) == 0 {
+		metrics.LLMCalls.WithLabelValues("empty").Inc()
+		slog.WarnContext(ctx, "LLM returned no candidates or parts")
 		return matchedItems, nil
 	}
 
 	responseText := strings.TrimSpace(resp.Candidates[0].Content.Parts[0].Text)
 
+	malformed := 0
 	for i, line := range strings.Split(responseText, "\n") {
-		splittedStr := strings.Split(line, ":")
-		originalName := strings.TrimSpace(splittedStr[0])
-		responseName := strings.TrimSpace(splittedStr[1])
+		// Listing titles may contain colons, model names do not.
+		separator := strings.LastIndex(line, ":")
+		if separator == -1 {
+			if strings.TrimSpace(line) != "" {
+				malformed++
+			}
+			continue
+		}
+		originalName := strings.TrimSpace(line[:separator])
+		responseName := strings.TrimSpace(line[separator+1:])
 
 		matched, itemName := matcher.MatchItem(responseName, searchTerms)
 		if matched {
@@ -63,9 +99,28 @@ func (c *LLMClient) CheckMatches(itemDescriptions []string, searchTerms []string
 				MatchedName:  itemName,
 			}
 			matchedItems = append(matchedItems, item)
-			fmt.Printf("Matched Item: %s -> %s\n", originalName, itemName)
+			slog.DebugContext(ctx, "Matched item", "name", originalName, "model", itemName)
 		}
 	}
 
+	if malformed > 0 {
+		metrics.LLMCalls.WithLabelValues("malformed").Inc()
+		slog.WarnContext(ctx, "Skipped malformed LLM response lines", "lines", malformed)
+	} else {
+		metrics.LLMCalls.WithLabelValues("ok").Inc()
+	}
 	return matchedItems, nil
 }
+
+func recordUsage(span trace.Span, usage *genai.GenerateContentResponseUsageMetadata) {
+	if usage == nil {
+		return
+	}
+	span.SetAttributes(
+		attribute.Int("gen_ai.usage.input_tokens", int(usage.PromptTokenCount)),
+		attribute.Int("gen_ai.usage.output_tokens", int(usage.CandidatesTokenCount)),
+	)
+	metrics.LLMTokens.WithLabelValues("prompt").Add(float64(usage.PromptTokenCount))
+	metrics.LLMTokens.WithLabelValues("completion").Add(float64(usage.CandidatesTokenCount))
+	metrics.LLMTokens.WithLabelValues("total").Add(float64(usage.TotalTokenCount))
+}
@@ -0,0 +1,153 @@
+package logging
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"regexp"
+	"slices"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+const redacted = "[REDACTED]"
+
+// Options configure the logger built by New.
+type Options struct {
+	Level slog.Leveler
+	// Format is "json" or "text".
+	Format string
+	// Secrets are replaced wherever they appear in a log line.
+	Secrets []string
+	// RedactIDs replaces LINE user, group and room IDs with a short hash, so
+	// lines about the same chat can still be correlated.
+	RedactIDs bool
+}
+
+// New returns a logger that adds the attributes carried by the context of
+// each call, see With.
+func New(w io.Writer, opts Options) *slog.Logger {
+	r := &redactor{redactIDs: opts.RedactIDs}
+	for _, secret := range opts.Secrets {
+		// Very short values would mangle unrelated text.
+		if len(secret) >= 8 {
+			r.secrets = append(r.secrets, secret)
+		}
+	}
+
+	handlerOpts := &slog.HandlerOptions{Level: opts.Level, ReplaceAttr: r.replaceAttr}
+	var h slog.Handler
+	if opts.Format == "text" {
+		h = slog.NewTextHandler(w, handlerOpts)
+	} else {
+		h = slog.NewJSONHandler(w, handlerOpts)
+	}
+	return slog.New(contextHandler{h})
+}
+
+type attrsKey struct{}
+
+// With returns a context whose log lines carry the attributes.
+func With(ctx context.Context, attrs ...slog.Attr) context.Context {
+	existing, _ := ctx.Value(attrsKey{}).([]slog.Attr)
+	return context.WithValue(ctx, attrsKey{}, append(slices.Clip(existing), attrs...))
+}
+
+// WithJob tags log lines with the queued job being processed.
+func WithJob(ctx context.Context, jobID int64) context.Context {
+	return With(ctx, slog.Int64("job_id", jobID))
+}
+
+// WithRun tags log lines with the pipeline run they belong to.
+func WithRun(ctx context.Context, runID int64) context.Context {
+	return With(ctx, slog.Int64("run_id", runID))
+}
+
+// WithEvent tags log lines with the LINE webhook event being handled and the
+// chat and user it came from.
+func WithEvent(ctx context.Context, eventID, sourceID, userID string) context.Context {
+	var attrs []slog.Attr
+	if eventID != "" {
+		attrs = append(attrs, slog.String("event_id", eventID))
+	}
+	if sourceID != "" {
+		attrs = append(attrs, slog.String("source_id", sourceID))
+	}
+	if userID != "" && userID != sourceID {
+		attrs = append(attrs, slog.String("user_id", userID))
+	}
+	return With(ctx, attrs...)
+}
+
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if attrs, ok := ctx.Value(attrsKey{}).([]slog.Attr); ok {
+		r.AddAttrs(attrs...)
+	}
+	if span := trace.SpanContextFromContext(ctx); span.IsValid() {
+		r.AddAttrs(slog.String("trace_id", span.TraceID().String()), slog.String("span_id", span.SpanID().String()))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
+
+// sensitiveKeys are attributes whose values are never logged.
+var sensitiveKeys = map[string]bool{
+	"token":         true,
+	"secret":        true,
+	"api_key":       true,
+	"password":      true,
+	"authorization": true,
+}
+
+// lineIDPattern matches the IDs of LINE users (U), groups (C) and rooms (R).
+var lineIDPattern = regexp.MustCompile(`\b[UCR][0-9a-f]{32}\b`)
+
+type redactor struct {
+	secrets   []string
+	redactIDs bool
+}
+
+func (r *redactor) replaceAttr(_ []string, a slog.Attr) slog.Attr {
+	if sensitiveKeys[strings.ToLower(a.Key)] {
+		return slog.String(a.Key, redacted)
+	}
+	switch a.Value.Kind() {
+	case slog.KindString:
+		return slog.String(a.Key, r.redact(a.Value.String()))
+	case slog.KindAny:
+		if err, ok := a.Value.Any().(error); ok {
+			return slog.String(a.Key, r.redact(err.Error()))
+		}
+	}
+	return a
+}
+
+func (r *redactor) redact(s string) string {
+	for _, secret := range r.secrets {
+		s = strings.ReplaceAll(s, secret, redacted)
+	}
+	if r.redactIDs {
+		s = lineIDPattern.ReplaceAllStringFunc(s, hashID)
+	}
+	return s
+}
+
+// hashID keeps the kind of a LINE ID and replaces the rest with a hash.
+func hashID(id string) string {
+	sum := sha256.Sum256([]byte(id))
+	return id[:1] + "#" + hex.EncodeToString(sum[:5])
+}
@@ -1,33 +1,160 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
-	"net/http"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+	_ "time/tzdata"
 
 	"github.com/drifterz13/dino-noti/config"
+	"github.com/drifterz13/dino-noti/logging"
 	"github.com/drifterz13/dino-noti/service"
-
-	"os"
+	"github.com/drifterz13/dino-noti/store"
+	"github.com/drifterz13/dino-noti/tracing"
 )
 
+type command struct {
+	name        string
+	description string
+	run         func(args []string) error
+}
+
+var commands = []command{
+	{"serve", "Run the bot server (the default)", serve},
+	{"scrape", "Scrape search pages and print the listings", scrape},
+	{"match", "Match scraped listings against the search terms", match},
+	{"run", "Run the full pipeline once", runOnce},
+	{"watchlist", "List, add or remove watched models", watchlist},
+	{"export", "Export listings as CSV, JSON Lines, Atom or RSS", exportItems},
+	{"feed", "Print the secret feed URLs of a chat", feedURLs},
+}
+
 func main() {
-	cfg, err := config.LoadConfig()
+	args := os.Args[1:]
+	// Without a subcommand the binary serves, as it did before it had any.
+	if len(args) == 0 {
+		args = []string{"serve"}
+	}
+
+	switch args[0] {
+	case "help", "-h", "--help":
+		usage()
+		return
+	}
+
+	for _, cmd := range commands {
+		if cmd.name == args[0] {
+			if err := cmd.run(args[1:]); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "Unknown command %q\n\n", args[0])
+	usage()
+	os.Exit(2)
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: %s <command> [flags]\n\nCommands:\n", os.Args[0])
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", cmd.name, cmd.description)
+	}
+	fmt.Fprintf(os.Stderr, "\nRun '%s <command> -h' for the flags of a command.\n", os.Args[0])
+}
+
+func usageFor(flags *flag.FlagSet, synopsis, description string) func() {
+	return func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s %s\n\n%s\n", os.Args[0], synopsis, description)
+		if hasFlags(flags) {
+			fmt.Fprintln(os.Stderr, "\nFlags:")
+			flags.PrintDefaults()
+		}
+	}
+}
+
+func hasFlags(flags *flag.FlagSet) bool {
+	found := false
+	flags.VisitAll(func(*flag.Flag) { found = true })
+	return found
+}
+
+// loadConfig loads the configuration for a command that needs the given
+// secrets and sends the logs to stderr as it configures.
+func loadConfig(required ...config.Requirement) (*config.Config, error) {
+	cfg, err := config.LoadConfig(required...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	slog.SetDefault(logging.New(os.Stderr, logging.Options{
+		Level:     cfg.LogLevel,
+		Format:    cfg.LogFormat,
+		Secrets:   cfg.Secrets(),
+		RedactIDs: cfg.RedactIDs,
+	}))
+	return cfg, nil
+}
+
+// openService opens the database and builds the service for a command that
+// needs the given secrets. close releases the database.
+func openService(required ...config.Requirement) (srv *service.Service, close func(), err error) {
+	cfg, err := loadConfig(required...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	stopTracing, err := startTracing(cfg)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error loading configuration: %v\n", err)
-		os.Exit(1)
+		return nil, nil, err
 	}
 
-	srv := service.NewService(cfg)
+	st, err := store.NewStore(cfg.DatabasePath)
+	if err != nil {
+		stopTracing()
+		return nil, nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	return service.NewService(cfg, st), func() {
+		st.Close()
+		stopTracing()
+	}, nil
+}
+
+// tracingFlushTimeout bounds how long exiting waits to send buffered spans.
+const tracingFlushTimeout = 5 * time.Second
 
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8000"
+// startTracing exports spans when an OTLP endpoint is configured. stop sends
+// the spans that are still buffered.
+func startTracing(cfg *config.Config) (stop func(), err error) {
+	if cfg.OTLPEndpoint == "" {
+		return func() {}, nil
 	}
-	http.HandleFunc("/callback", srv.HandleLineMessageReq)
 
-	fmt.Printf("Listening on port %s\n", port)
-	if err := http.ListenAndServe(":"+port, nil); err != nil && err != http.ErrServerClosed {
-		fmt.Fprintf(os.Stderr, "HTTP server error: %v\n", err)
-		os.Exit(1)
+	shutdown, err := tracing.Setup(context.Background())
+	if err != nil {
+		return nil, err
 	}
+	slog.Info("Exporting traces", "endpoint", cfg.OTLPEndpoint)
+
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), tracingFlushTimeout)
+		defer cancel()
+		if err := shutdown(ctx); err != nil {
+			slog.Error("Error flushing traces", "err", err)
+		}
+	}, nil
+}
+
+// commandContext is cancelled on Ctrl-C, so commands stop early and still
+// print what they got so far.
+func commandContext() (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 }
@@ -0,0 +1,37 @@
+package market
+
+import "strings"
+
+type Condition string
+
+const (
+	ConditionJunk      Condition = "junk"
+	ConditionNew       Condition = "new"
+	ConditionExcellent Condition = "excellent"
+	ConditionWorking   Condition = "working"
+	ConditionUnknown   Condition = "unknown"
+)
+
+// conditionKeywords is checked in order, so a "ジャンク 美品" title is junk.
+var conditionKeywords = []struct {
+	condition Condition
+	keywords  []string
+}{
+	{ConditionJunk, []string{"ジャンク", "junk", "難あり", "動作未確認", "部品取り"}},
+	{ConditionNew, []string{"新品", "未使用", "未開封"}},
+	{ConditionExcellent, []string{"極美品", "超美品", "美品"}},
+	{ConditionWorking, []string{"動作品", "動作確認済", "稼働品", "完動品", "動作ok", "良品"}},
+}
+
+// ConditionOf buckets a listing by the condition keywords in its title.
+func ConditionOf(title string) Condition {
+	lower := strings.ToLower(title)
+	for _, c := range conditionKeywords {
+		for _, keyword := range c.keywords {
+			if strings.Contains(lower, keyword) {
+				return c.condition
+			}
+		}
+	}
+	return ConditionUnknown
+}
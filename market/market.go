@@ -0,0 +1,84 @@
+package market
+
+import (
+	"sort"
+
+	"github.com/drifterz13/dino-noti/model"
+)
+
+// MinSamples is the number of observations a condition bucket needs before
+// it is used instead of every observation of the model.
+const MinSamples = 5
+
+type Estimate struct {
+	ReferencePrice int
+	Condition      Condition
+	Samples        int
+	// DiffPercent is how far the price is above (positive) or below
+	// (negative) the reference price.
+	DiffPercent float64
+	// Percentile is the share of observed prices at or below the price.
+	Percentile float64
+}
+
+// EstimatePrice compares a listing against historical observations of the same
+// canonical model, preferring observations in the same condition bucket.
+func EstimatePrice(title string, price int, history []model.ListingPrice) (Estimate, bool) {
+	condition := ConditionOf(title)
+
+	var bucket, all []int
+	for _, observation := range history {
+		all = append(all, observation.Price)
+		if ConditionOf(observation.Name) == condition {
+			bucket = append(bucket, observation.Price)
+		}
+	}
+
+	prices := bucket
+	if len(bucket) < MinSamples {
+		prices = all
+	}
+	if len(prices) < MinSamples {
+		return Estimate{}, false
+	}
+
+	sort.Ints(prices)
+	reference := Median(prices)
+	if reference == 0 {
+		return Estimate{}, false
+	}
+
+	atOrBelow := sort.SearchInts(prices, price+1)
+
+	return Estimate{
+		ReferencePrice: reference,
+		Condition:      condition,
+		Samples:        len(prices),
+		DiffPercent:    float64(price-reference) / float64(reference) * 100,
+		Percentile:     float64(atOrBelow) / float64(len(prices)) * 100,
+	}, true
+}
+
+// Median expects sorted prices.
+func Median(sorted []int) int {
+	if len(sorted) == 0 {
+		return 0
+	}
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
+// SortByDeal orders items from the furthest below market to the furthest
+// above, keeping items without a market price last.
+func SortByDeal(items []model.MatchedItem) {
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := items[i], items[j]
+		if (a.MarketPrice > 0) != (b.MarketPrice > 0) {
+			return a.MarketPrice > 0
+		}
+		return a.MarketDiffPercent < b.MarketDiffPercent
+	})
+}
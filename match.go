@@ -0,0 +1,75 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/drifterz13/dino-noti/config"
+	"github.com/drifterz13/dino-noti/model"
+)
+
+func match(args []string) error {
+	flags := flag.NewFlagSet("match", flag.ExitOnError)
+	input := flags.String("input", "", "`file` of listings as printed by scrape -format json, or - for stdin")
+	format := flags.String("format", "table", "output `format`: table, json or csv")
+	flags.Usage = usageFor(flags, "match -input items.json [flags]", "Match scraped listings against the search terms and watchlists, without recording them.")
+	flags.Parse(args)
+
+	if *input == "" {
+		flags.Usage()
+		return fmt.Errorf("-input is required")
+	}
+	if err := checkFormat(*format); err != nil {
+		return err
+	}
+
+	scrapedItems, err := readScrapeItems(*input)
+	if err != nil {
+		return err
+	}
+
+	srv, close, err := openService(config.RequireGemini)
+	if err != nil {
+		return err
+	}
+	defer close()
+
+	ctx, stop := commandContext()
+	defer stop()
+
+	var scraped []model.ScrapeItem
+	for _, item := range scrapedItems {
+		scraped = append(scraped, item.model())
+	}
+	matchedItems, matchErr := srv.MatchItems(ctx, scraped)
+
+	items := []matchedItem{}
+	for _, item := range matchedItems {
+		items = append(items, newMatchedItem(item))
+	}
+	if err := writeOutput(os.Stdout, *format, items, matchedItemsTable(items)); err != nil {
+		return err
+	}
+	return matchErr
+}
+
+func readScrapeItems(path string) ([]scrapeItem, error) {
+	var r io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open input: %w", err)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	var items []scrapeItem
+	if err := json.NewDecoder(r).Decode(&items); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return items, nil
+}
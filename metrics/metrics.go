@@ -0,0 +1,114 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "dinonoti"
+
+var registry = prometheus.NewRegistry()
+
+var factory = promauto.With(registry)
+
+var (
+	PagesFetched = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "scrape_pages_fetched_total",
+		Help:      "Search result pages fetched, by target and HTTP status code, or \"error\" when no response arrived.",
+	}, []string{"target", "code"})
+
+	FetchDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "scrape_fetch_duration_seconds",
+		Help:      "Time taken to fetch a search result page, by target.",
+		Buckets:   []float64{0.1, 0.25, 0.5, 1, 2, 4, 8, 15},
+	}, []string{"target"})
+
+	ItemsPerPage = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "scrape_items_per_page",
+		Help:      "Items parsed from a fetched page, by target.",
+		Buckets:   []float64{0, 1, 5, 10, 20, 30, 50, 100},
+	}, []string{"target"})
+
+	EmptyPages = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "scrape_empty_pages_total",
+		Help:      "Fetched pages the parser found no items on, by target. A rise usually means the page layout changed.",
+	}, []string{"target"})
+
+	LLMCalls = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "llm_calls_total",
+		Help:      "Matching calls to the LLM, by result: ok, malformed when some response lines could not be read, empty or error.",
+	}, []string{"result"})
+
+	LLMDuration = factory.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "llm_call_duration_seconds",
+		Help:      "Time taken by a matching call to the LLM.",
+		Buckets:   []float64{0.25, 0.5, 1, 2, 4, 6, 8, 10},
+	})
+
+	LLMTokens = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "llm_tokens_total",
+		Help:      "Tokens used by matching calls to the LLM, by kind: prompt, completion or total.",
+	}, []string{"kind"})
+
+	RunMatches = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "run_matches",
+		Help:      "Items matched to a model per finished run, by trigger.",
+		Buckets:   []float64{0, 1, 2, 5, 10, 20, 50, 100},
+	}, []string{"trigger"})
+
+	Runs = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "runs_total",
+		Help:      "Finished runs, by trigger and result: ok, degraded or failed.",
+	}, []string{"trigger", "result"})
+
+	LineRequests = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "line_api_requests_total",
+		Help:      "Calls to the LINE Messaging API, by operation and HTTP status code, or \"error\" when no response arrived.",
+	}, []string{"operation", "code"})
+
+	LineDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "line_api_duration_seconds",
+		Help:      "Time taken by calls to the LINE Messaging API, by operation.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"operation"})
+
+	WebhookRequests = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "webhook_requests_total",
+		Help:      "LINE webhook requests, by result: ok or rejected.",
+	}, []string{"result"})
+
+	WebhookDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "webhook_event_duration_seconds",
+		Help:      "Time from receiving a webhook event until it was handled, by event type and source kind.",
+		Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2, 5, 10, 30},
+	}, []string{"event", "source"})
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+// Handler serves every metric in the Prometheus text format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
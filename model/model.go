@@ -1,17 +1,430 @@
 package model
 
+import "time"
+
 type MatchedItem struct {
 	Index        int
+	AuctionID    string
 	URL          string
 	OriginalName string
 	MatchedName  string
 	Price        string
 	ImageURL     string
+	// MarketPrice is zero when there is not enough history for the model.
+	MarketPrice       int
+	MarketDiffPercent float64
+	MarketPercentile  float64
+	Auction           AuctionDetails
+	// PriceTHB and LandedTHB are estimates in Thai baht, the latter including
+	// fees and shipping. They are zero when no exchange rate is configured.
+	PriceTHB  int
+	LandedTHB int
+	// IsNew is set for listings seen for the first time, PreviousPrice for
+	// listings that got cheaper since they were last seen.
+	IsNew         bool
+	PreviousPrice int
 }
 
 type ScrapeItem struct {
-	URL      string
+	AuctionID string
+	URL       string
+	Name      string
+	Price     string
+	ImageURL  string
+	Auction   AuctionDetails
+}
+
+// AuctionDetails is the optional metadata of a listing. Fields are zero when
+// the search page does not show them.
+type AuctionDetails struct {
+	BuyoutPrice  string
+	Bids         int
+	EndsAt       time.Time
+	Seller       string
+	SellerRating string
+}
+
+type RunTrigger string
+
+const (
+	TriggerWebhook  RunTrigger = "webhook"
+	TriggerSchedule RunTrigger = "schedule"
+	TriggerCLI      RunTrigger = "cli"
+	TriggerAPI      RunTrigger = "api"
+)
+
+// Target is a Buyee search results page the pipeline scrapes, up to MaxPages
+// pages deep.
+type Target struct {
+	ID        int64
+	Name      string
+	URL       string
+	MaxPages  int
+	Enabled   bool
+	CreatedAt time.Time
+}
+
+type Run struct {
+	ID           int64
+	StartedAt    time.Time
+	FinishedAt   time.Time
+	Trigger      RunTrigger
+	PagesScraped int
+	ItemsFound   int
+	LLMBatches   int
+	Matches      int
+	Errors       []string
+	// Anomalies describe what looked wrong with the scrape, e.g. a page
+	// layout change that left the parser finding nothing.
+	Anomalies []string
+}
+
+// Degraded reports whether the results of the run can't be trusted.
+func (r Run) Degraded() bool {
+	return len(r.Anomalies) > 0
+}
+
+type PriceChange struct {
+	Item     MatchedItem
+	OldPrice string
+}
+
+type RunDiff struct {
+	Previous     Run
+	Current      Run
+	Appeared     []MatchedItem
+	Disappeared  []MatchedItem
+	PriceChanged []PriceChange
+}
+
+type PriceDrop struct {
+	Item     MatchedItem
+	OldPrice int
+	NewPrice int
+	// RelistOf is the auction ID of the earlier listing when the item is a
+	// cheaper relist rather than a price change of the same listing.
+	RelistOf string
+}
+
+// Listing is a matched auction as last seen by the pipeline.
+type Listing struct {
+	AuctionID   string
+	URL         string
+	Name        string
+	ImageURL    string
+	MatchedName string
+	Price       int
+	FirstSeenAt time.Time
+	LastSeenAt  time.Time
+}
+
+// ListingFilter narrows listings down to a model and a time range. Zero
+// values leave the corresponding bound open.
+type ListingFilter struct {
+	Model string
+	// Models, when set, keeps the listings of any of these models.
+	Models []string
+	Since  time.Time
+	Until  time.Time
+	Limit  int
+}
+
+// PricePoint is the price of a listing as observed by a run.
+type PricePoint struct {
+	AuctionID  string
+	RunID      int64
+	Price      int
+	ObservedAt time.Time
+}
+
+type ListingPrice struct {
+	AuctionID string
+	Name      string
+	Price     int
+}
+
+type PriceStats struct {
+	Model  string
+	Days   int
+	Count  int
+	Min    int
+	Median int
+	Max    int
+}
+
+type SourceKind string
+
+const (
+	SourceUser  SourceKind = "user"
+	SourceGroup SourceKind = "group"
+	SourceRoom  SourceKind = "room"
+)
+
+type Subscriber struct {
+	ID         string
+	Kind       SourceKind
+	CreatedAt  time.Time
+	MutedUntil time.Time
+	// Active is false once the user unfollows or the bot leaves the chat.
+	Active       bool
+	Preferences  Preferences
+	LastDigestAt time.Time
+}
+
+// SubscriberUpdate changes the fields that are set and leaves nil ones as is.
+type SubscriberUpdate struct {
+	Active      *bool
+	MutedUntil  *time.Time
+	Preferences *Preferences
+}
+
+func (s Subscriber) Muted(now time.Time) bool {
+	return now.Before(s.MutedUntil)
+}
+
+type DigestMode string
+
+const (
+	DigestImmediate DigestMode = "immediate"
+	DigestHourly    DigestMode = "hourly"
+	DigestDaily     DigestMode = "daily"
+)
+
+// Interval is how long a digest waits after the previous one.
+func (d DigestMode) Interval() time.Duration {
+	switch d {
+	case DigestHourly:
+		return time.Hour
+	case DigestDaily:
+		return 24 * time.Hour
+	default:
+		return 0
+	}
+}
+
+// Preferences control when and what a subscriber is notified about.
+type Preferences struct {
+	// Language is a catalog language code; empty means the configured default.
+	Language string
+	// Timezone is an IANA name; empty means the configured default.
+	Timezone string
+	// QuietStart and QuietEnd are minutes after local midnight. The period
+	// may wrap past midnight and is disabled when both are equal.
+	QuietStart int
+	QuietEnd   int
+	Digest     DigestMode
+	// MinDiscount is how far below the market price, in percent, a listing
+	// must be to be alerted as a deal.
+	MinDiscount float64
+}
+
+func (p Preferences) HasQuietHours() bool {
+	return p.QuietStart != p.QuietEnd
+}
+
+// QuietAt reports whether a time, already in the subscriber's timezone, falls
+// in the quiet hours.
+func (p Preferences) QuietAt(local time.Time) bool {
+	if !p.HasQuietHours() {
+		return false
+	}
+	minute := local.Hour()*60 + local.Minute()
+	if p.QuietStart < p.QuietEnd {
+		return minute >= p.QuietStart && minute < p.QuietEnd
+	}
+	return minute >= p.QuietStart || minute < p.QuietEnd
+}
+
+// AllowsDeal reports whether an item is discounted enough to be alerted.
+func (p Preferences) AllowsDeal(item MatchedItem) bool {
+	return -item.MarketDiffPercent >= p.MinDiscount
+}
+
+// WatchEntry is a model on a chat's watchlist. In groups, AddedBy is the
+// member who added it.
+type WatchEntry struct {
+	SourceID    string
+	Model       string
+	AddedBy     string
+	AddedByName string
+	CreatedAt   time.Time
+}
+
+// ItemFilter hides listings and models a subscriber chose to ignore.
+type ItemFilter struct {
+	IgnoredListings map[string]bool
+	IgnoredModels   map[string]bool
+}
+
+func (f ItemFilter) Allows(item MatchedItem) bool {
+	return !f.IgnoredListings[item.AuctionID] && !f.IgnoredModels[item.MatchedName]
+}
+
+func (f ItemFilter) Apply(items []MatchedItem) []MatchedItem {
+	var visible []MatchedItem
+	for _, item := range items {
+		if f.Allows(item) {
+			visible = append(visible, item)
+		}
+	}
+	return visible
+}
+
+type DeliveryMode string
+
+const (
+	DeliveryReply DeliveryMode = "reply"
+	DeliveryPush  DeliveryMode = "push"
+)
+
+type Delivery struct {
+	ID        int64
+	SourceID  string
+	Channel   Channel
+	Mode      DeliveryMode
+	Messages  int
+	Error     string
+	CreatedAt time.Time
+}
+
+type Channel string
+
+const (
+	ChannelLine    Channel = "line"
+	ChannelDiscord Channel = "discord"
+	ChannelSlack   Channel = "slack"
+	ChannelEmail   Channel = "email"
+	ChannelWebhook Channel = "webhook"
+)
+
+var Channels = []Channel{ChannelLine, ChannelDiscord, ChannelSlack, ChannelEmail, ChannelWebhook}
+
+// Route is a channel a subscriber's notifications are sent to. Target is the
+// LINE chat ID, webhook URL or email address, depending on the channel.
+type Route struct {
+	Channel Channel
+	Target  string
+}
+
+// Notification is a batch of matched items pushed to a subscriber, e.g. the
+// deals found by a run.
+type Notification struct {
+	// Language is the catalog language the notification was rendered in.
+	Language string
+	Title    string
+	Items    []MatchedItem
+	// Details holds an extra line per item keyed by auction ID, such as the
+	// previous price of a price drop.
+	Details map[string]string
+}
+
+func (n Notification) Detail(item MatchedItem) string {
+	return n.Details[item.AuctionID]
+}
+
+// PendingNotification is a notification held back by quiet hours or digest
+// mode until the subscriber's next digest.
+type PendingNotification struct {
+	ID           int64
+	Notification Notification
+	CreatedAt    time.Time
+}
+
+type JobStatus string
+
+const (
+	JobQueued  JobStatus = "queued"
+	JobRunning JobStatus = "running"
+	JobDone    JobStatus = "done"
+	// JobDead jobs ran out of attempts and were copied to the dead letters.
+	JobDead JobStatus = "dead"
+)
+
+// JobStage is the pipeline step a job resumes from.
+type JobStage string
+
+const (
+	StageScrape JobStage = "scrape"
+	StageMatch  JobStage = "match"
+	StageRecord JobStage = "record"
+	StageNotify JobStage = "notify"
+	StageDone   JobStage = "done"
+)
+
+// Job is a queued pipeline run. Its checkpoint holds the output of every
+// finished stage, so a retried or interrupted job picks up where it stopped.
+type Job struct {
+	ID         int64
+	Trigger    RunTrigger
+	Status     JobStatus
+	Stage      JobStage
+	Attempts   int
+	RunID      int64
+	Checkpoint JobCheckpoint
+	LastError  string
+	RunAfter   time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	// TraceParent is the trace of the request that queued the job.
+	TraceParent string
+}
+
+type JobCheckpoint struct {
+	Scraped      []ScrapeItem `json:",omitempty"`
+	PagesScraped int          `json:",omitempty"`
+	// BadStatuses counts the pages that answered with a status other than 200.
+	BadStatuses int           `json:",omitempty"`
+	Errors      []string      `json:",omitempty"`
+	Anomalies   []string      `json:",omitempty"`
+	Matched     []MatchedItem `json:",omitempty"`
+	LLMBatches  int           `json:",omitempty"`
+	Deals       []MatchedItem `json:",omitempty"`
+	Drops       []PriceDrop   `json:",omitempty"`
+	// Notified is saved before the alerts of the run are sent, so a retried
+	// notify stage does not send them twice.
+	Notified bool `json:",omitempty"`
+}
+
+// JobRequester is a chat waiting for the results of a job, e.g. the user who
+// sent the search command that queued it.
+type JobRequester struct {
+	SourceID   string
+	AsCarousel bool
+}
+
+// DeadJob is a job that failed on every attempt, kept for inspection.
+type DeadJob struct {
+	ID         int64
+	JobID      int64
+	Trigger    RunTrigger
+	Stage      JobStage
+	Attempts   int
+	Error      string
+	Checkpoint JobCheckpoint
+	FailedAt   time.Time
+}
+
+// TargetScrape is the last time a target was scraped with at least one page
+// fetched.
+type TargetScrape struct {
+	URL       string
+	ScrapedAt time.Time
+	Pages     int
+	Items     int
+}
+
+type CheckStatus string
+
+const (
+	CheckOK   CheckStatus = "ok"
+	CheckWarn CheckStatus = "warn"
+	CheckFail CheckStatus = "fail"
+)
+
+// HealthCheck is the outcome of one readiness or diagnostic check.
+type HealthCheck struct {
 	Name     string
-	Price    string
-	ImageURL string
+	Status   CheckStatus
+	Detail   string
+	Duration time.Duration
 }
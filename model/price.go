@@ -0,0 +1,17 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParsePrice converts a scraped yen price such as "12,345" into an integer.
+func ParsePrice(price string) (int, error) {
+	cleaned := strings.NewReplacer(",", "", "円", "", "yen", "", " ", "").Replace(strings.TrimSpace(price))
+	yen, err := strconv.Atoi(cleaned)
+	if err != nil {
+		return 0, fmt.Errorf("invalid price %q: %w", price, err)
+	}
+	return yen, nil
+}
@@ -0,0 +1,75 @@
+package notify
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/drifterz13/dino-noti/model"
+)
+
+// Discord accepts at most 10 embeds per message.
+const discordMaxEmbeds = 10
+
+// DiscordNotifier posts notifications as embeds to a Discord webhook URL.
+type DiscordNotifier struct {
+	client *http.Client
+}
+
+func NewDiscordNotifier(client *http.Client) *DiscordNotifier {
+	if client == nil {
+		client = defaultHTTPClient
+	}
+	return &DiscordNotifier{client: client}
+}
+
+type discordMessage struct {
+	Content string         `json:"content,omitempty"`
+	Embeds  []discordEmbed `json:"embeds"`
+}
+
+type discordEmbed struct {
+	Title       string            `json:"title"`
+	URL         string            `json:"url,omitempty"`
+	Description string            `json:"description,omitempty"`
+	Color       int               `json:"color"`
+	Thumbnail   *discordThumbnail `json:"thumbnail,omitempty"`
+}
+
+type discordThumbnail struct {
+	URL string `json:"url"`
+}
+
+func (d *DiscordNotifier) Notify(ctx context.Context, target string, n model.Notification) error {
+	p := printer(n)
+	var embeds []discordEmbed
+	for _, item := range n.Items {
+		description := []string{p.YenString(item.Price)}
+		if detail := n.Detail(item); detail != "" {
+			description = append(description, detail)
+		}
+		description = append(description, item.OriginalName)
+
+		embed := discordEmbed{
+			Title:       item.MatchedName,
+			URL:         item.URL,
+			Description: strings.Join(description, "\n"),
+			Color:       0x2e7d32,
+		}
+		if item.ImageURL != "" {
+			embed.Thumbnail = &discordThumbnail{URL: item.ImageURL}
+		}
+		embeds = append(embeds, embed)
+	}
+
+	for i, batch := range chunk(embeds, discordMaxEmbeds) {
+		message := discordMessage{Embeds: batch}
+		if i == 0 {
+			message.Content = n.Title
+		}
+		if err := postJSON(ctx, d.client, target, message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
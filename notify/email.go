@@ -0,0 +1,198 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"html/template"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"time"
+
+	"github.com/drifterz13/dino-noti/config"
+	"github.com/drifterz13/dino-noti/model"
+)
+
+// EmailNotifier sends notifications as HTML emails with a plain text
+// alternative through an SMTP server.
+type EmailNotifier struct {
+	addr     string
+	host     string
+	username string
+	password string
+	from     string
+	// dial connects to the SMTP server, so tests can stand one in.
+	dial func(ctx context.Context, network, addr string) (net.Conn, error)
+}
+
+func NewEmailNotifier(cfg *config.Config) *EmailNotifier {
+	return &EmailNotifier{
+		addr:     net.JoinHostPort(cfg.SMTPHost, strconv.Itoa(cfg.SMTPPort)),
+		host:     cfg.SMTPHost,
+		username: cfg.SMTPUsername,
+		password: cfg.SMTPPassword,
+		from:     cfg.SMTPFrom,
+		dial:     (&net.Dialer{}).DialContext,
+	}
+}
+
+var emailTemplate = template.Must(template.New("email").Parse(`<!DOCTYPE html>
+<html>
+<body style="font-family: sans-serif;">
+<h2>{{.Title}}</h2>
+<table cellpadding="8">
+{{range .Items}}<tr>
+<td>{{if .Item.ImageURL}}<img src="{{.Item.ImageURL}}" alt="" width="120">{{end}}</td>
+<td>
+<a href="{{.Item.URL}}"><strong>{{.Item.MatchedName}}</strong></a><br>
+{{.Price}}{{if .Detail}} · {{.Detail}}{{end}}<br>
+<small>{{.Item.OriginalName}}</small>
+</td>
+</tr>
+{{end}}</table>
+</body>
+</html>
+`))
+
+type emailItem struct {
+	Item   model.MatchedItem
+	Price  string
+	Detail string
+}
+
+func (e *EmailNotifier) Notify(ctx context.Context, target string, n model.Notification) error {
+	if e.host == "" {
+		return errors.New("email notifications are not configured, set SMTP_HOST")
+	}
+
+	// Routes added before targets were reduced to bare addresses may still
+	// carry a display name.
+	to, err := mail.ParseAddress(target)
+	if err != nil {
+		return fmt.Errorf("invalid email address %q: %w", target, err)
+	}
+	target = to.Address
+
+	message, err := e.buildMessage(target, n)
+	if err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if e.username != "" {
+		auth = smtp.PlainAuth("", e.username, e.password, e.host)
+	}
+	if err := e.sendMail(ctx, auth, target, message); err != nil {
+		return fmt.Errorf("failed to send email to %s: %w", target, err)
+	}
+	return nil
+}
+
+// sendMail does what smtp.SendMail does, but dials with ctx and gives up on
+// the whole exchange once ctx is done.
+func (e *EmailNotifier) sendMail(ctx context.Context, auth smtp.Auth, to string, message []byte) error {
+	conn, err := e.dial(ctx, "tcp", e.addr)
+	if err != nil {
+		return err
+	}
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	client, err := smtp.NewClient(conn, e.host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: e.host}); err != nil {
+			return err
+		}
+	}
+	if auth != nil {
+		if err := client.Auth(auth); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(e.from); err != nil {
+		return err
+	}
+	if err := client.Rcpt(to); err != nil {
+		return err
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(message); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+func (e *EmailNotifier) buildMessage(to string, n model.Notification) ([]byte, error) {
+	p := printer(n)
+	var items []emailItem
+	for _, item := range n.Items {
+		items = append(items, emailItem{Item: item, Price: p.YenString(item.Price), Detail: n.Detail(item)})
+	}
+
+	var html bytes.Buffer
+	if err := emailTemplate.Execute(&html, struct {
+		Title string
+		Items []emailItem
+	}{n.Title, items}); err != nil {
+		return nil, fmt.Errorf("failed to render email: %w", err)
+	}
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for _, part := range []struct {
+		contentType string
+		content     []byte
+	}{
+		{"text/plain; charset=UTF-8", []byte(RenderText(n))},
+		{"text/html; charset=UTF-8", html.Bytes()},
+	} {
+		w, err := writer.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create email part: %w", err)
+		}
+		qp := quotedprintable.NewWriter(w)
+		if _, err := qp.Write(part.content); err != nil {
+			return nil, fmt.Errorf("failed to write email part: %w", err)
+		}
+		qp.Close()
+	}
+	writer.Close()
+
+	var message bytes.Buffer
+	fmt.Fprintf(&message, "From: %s\r\n", e.from)
+	fmt.Fprintf(&message, "To: %s\r\n", to)
+	fmt.Fprintf(&message, "Subject: %s\r\n", mimeHeader(n.Title))
+	fmt.Fprintf(&message, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	message.WriteString("MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&message, "Content-Type: multipart/alternative; boundary=%s\r\n\r\n", writer.Boundary())
+	message.Write(body.Bytes())
+
+	return message.Bytes(), nil
+}
+
+func mimeHeader(value string) string {
+	return mime.BEncoding.Encode("UTF-8", value)
+}
@@ -0,0 +1,107 @@
+package notify
+
+import (
+	"context"
+	"net"
+	"net/textproto"
+	"strings"
+	"testing"
+
+	"github.com/drifterz13/dino-noti/model"
+)
+
+// fakeSMTP answers an SMTP session on conn, records the commands it was sent
+// and the message data, and closes the connection after QUIT.
+func fakeSMTP(conn net.Conn, commands *[]string, data *string) {
+	defer conn.Close()
+	tp := textproto.NewConn(conn)
+	tp.PrintfLine("220 localhost ESMTP")
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		*commands = append(*commands, line)
+		switch verb, _, _ := strings.Cut(line, " "); strings.ToUpper(verb) {
+		case "EHLO", "HELO":
+			tp.PrintfLine("250 localhost")
+		case "DATA":
+			tp.PrintfLine("354 go ahead")
+			lines, err := tp.ReadDotLines()
+			if err != nil {
+				return
+			}
+			*data = strings.Join(lines, "\n")
+			tp.PrintfLine("250 queued")
+		case "QUIT":
+			tp.PrintfLine("221 bye")
+			return
+		default:
+			tp.PrintfLine("250 ok")
+		}
+	}
+}
+
+func TestEmailNotifierSendsToBareAddress(t *testing.T) {
+	client, server := net.Pipe()
+	var commands []string
+	var data string
+	done := make(chan struct{})
+	go func() {
+		fakeSMTP(server, &commands, &data)
+		close(done)
+	}()
+
+	e := &EmailNotifier{
+		addr: "smtp.example.com:587",
+		host: "smtp.example.com",
+		from: "dino@example.com",
+		dial: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			return client, nil
+		},
+	}
+	n := model.Notification{
+		Language: "en",
+		Title:    "Deals on the radar",
+		Items:    []model.MatchedItem{{MatchedName: "Ricoh GR", Price: "30000", URL: "https://buyee.jp/item/x1"}},
+	}
+	if err := e.Notify(context.Background(), "Camera Fan <fan@example.com>", n); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+	<-done
+
+	for _, want := range []string{"MAIL FROM:<dino@example.com>", "RCPT TO:<fan@example.com>"} {
+		if !containsFold(commands, want) {
+			t.Errorf("commands %q do not include %q", commands, want)
+		}
+	}
+	header, _, _ := strings.Cut(data, "\n\n")
+	if !strings.Contains(header, "To: fan@example.com\n") {
+		t.Errorf("header does not address fan@example.com:\n%s", header)
+	}
+	if !strings.Contains(data, "Ricoh GR") {
+		t.Errorf("message does not mention the item:\n%s", data)
+	}
+}
+
+func TestEmailNotifierRejectsInvalidAddress(t *testing.T) {
+	e := &EmailNotifier{
+		host: "smtp.example.com",
+		dial: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			t.Fatal("dialed the SMTP server for an invalid address")
+			return nil, nil
+		},
+	}
+	if err := e.Notify(context.Background(), "not an address", model.Notification{}); err == nil {
+		t.Error("Notify succeeded for an invalid address")
+	}
+}
+
+func containsFold(lines []string, want string) bool {
+	for _, line := range lines {
+		if strings.EqualFold(line, want) {
+			return true
+		}
+	}
+	return false
+}
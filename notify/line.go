@@ -0,0 +1,27 @@
+package notify
+
+import (
+	"context"
+
+	"github.com/drifterz13/dino-noti/config"
+	"github.com/drifterz13/dino-noti/line"
+	"github.com/drifterz13/dino-noti/model"
+)
+
+// LineNotifier pushes notifications as LINE text messages to a user, group
+// or room ID.
+type LineNotifier struct {
+	cfg *config.Config
+}
+
+func NewLineNotifier(cfg *config.Config) *LineNotifier {
+	return &LineNotifier{cfg: cfg}
+}
+
+func (l *LineNotifier) Notify(ctx context.Context, target string, n model.Notification) error {
+	lineBotClient, err := line.NewLineBotClient(ctx, l.cfg)
+	if err != nil {
+		return err
+	}
+	return lineBotClient.PushMessages(target, line.TextMessages(RenderText(n))...)
+}
@@ -0,0 +1,139 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/drifterz13/dino-noti/i18n"
+	"github.com/drifterz13/dino-noti/model"
+)
+
+// Notifier delivers a notification to a target on one channel. The target is
+// channel specific: a LINE chat ID, a webhook URL or an email address.
+type Notifier interface {
+	Notify(ctx context.Context, target string, n model.Notification) error
+}
+
+// RenderText is the plain text form of a notification, used by LINE and as
+// the text part of emails.
+func RenderText(n model.Notification) string {
+	p := printer(n)
+	msg := strings.Builder{}
+	msg.WriteString(n.Title + ":\n")
+	for idx, item := range n.Items {
+		msg.WriteString(fmt.Sprintf("%d. (%s) %s - %s\n", idx+1, p.YenString(item.Price), item.MatchedName, item.URL))
+		if detail := n.Detail(item); detail != "" {
+			msg.WriteString("   " + detail + "\n")
+		}
+	}
+	return msg.String()
+}
+
+func printer(n model.Notification) *i18n.Printer {
+	return i18n.NewPrinter(i18n.Lang(n.Language))
+}
+
+// defaultHTTPClient only connects to public addresses, since webhook URLs
+// come from chat members and must not reach the host's own network.
+var defaultHTTPClient = &http.Client{
+	Timeout: 10 * time.Second,
+	Transport: &http.Transport{
+		DialContext: (&net.Dialer{
+			Timeout: 5 * time.Second,
+			Control: checkPublicAddress,
+		}).DialContext,
+		TLSHandshakeTimeout: 5 * time.Second,
+	},
+}
+
+var ErrPrivateAddress = errors.New("address is not public")
+
+// CheckWebhookURL fails unless the URL is https and names a host rather than
+// an IP address. The addresses the host resolves to are checked on connect.
+func CheckWebhookURL(target string) error {
+	u, err := url.Parse(target)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "https" || u.Hostname() == "" {
+		return errors.New("webhook URLs must use https")
+	}
+	if _, err := netip.ParseAddr(u.Hostname()); err == nil {
+		return errors.New("webhook URLs must use a host name")
+	}
+	if host := strings.ToLower(u.Hostname()); host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return ErrPrivateAddress
+	}
+	return nil
+}
+
+func checkPublicAddress(network, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+	addr := addrPort.Addr().Unmap()
+	if addr.IsLoopback() || addr.IsPrivate() || addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() ||
+		addr.IsMulticast() || addr.IsUnspecified() || sharedAddressSpace.Contains(addr) {
+		return fmt.Errorf("%s: %w", addr, ErrPrivateAddress)
+	}
+	return nil
+}
+
+// sharedAddressSpace is the carrier-grade NAT range, private in practice.
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+func postJSON(ctx context.Context, client *http.Client, target string, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", withoutURL(err))
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post to webhook: %w", withoutURL(err))
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("webhook returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+	return nil
+}
+
+// withoutURL drops the URL from an error of the url or http packages. Webhook
+// URLs carry their secret, and these errors are logged and stored with the
+// delivery.
+func withoutURL(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return urlErr.Err
+	}
+	return err
+}
+
+func chunk[T any](items []T, size int) [][]T {
+	var chunks [][]T
+	for size < len(items) {
+		items, chunks = items[size:], append(chunks, items[:size])
+	}
+	return append(chunks, items)
+}
@@ -0,0 +1,78 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/drifterz13/dino-noti/model"
+)
+
+// Slack accepts at most 50 blocks per message; one is used by the header.
+const slackMaxItems = 49
+
+// SlackNotifier posts notifications as Block Kit messages to a Slack incoming
+// webhook URL.
+type SlackNotifier struct {
+	client *http.Client
+}
+
+func NewSlackNotifier(client *http.Client) *SlackNotifier {
+	if client == nil {
+		client = defaultHTTPClient
+	}
+	return &SlackNotifier{client: client}
+}
+
+type slackMessage struct {
+	Text   string       `json:"text"`
+	Blocks []slackBlock `json:"blocks"`
+}
+
+type slackBlock struct {
+	Type      string          `json:"type"`
+	Text      *slackText      `json:"text,omitempty"`
+	Accessory *slackAccessory `json:"accessory,omitempty"`
+}
+
+type slackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type slackAccessory struct {
+	Type     string `json:"type"`
+	ImageURL string `json:"image_url"`
+	AltText  string `json:"alt_text"`
+}
+
+func (s *SlackNotifier) Notify(ctx context.Context, target string, n model.Notification) error {
+	p := printer(n)
+	for _, batch := range chunk(n.Items, slackMaxItems) {
+		blocks := []slackBlock{{
+			Type: "header",
+			Text: &slackText{Type: "plain_text", Text: n.Title},
+		}}
+
+		for _, item := range batch {
+			text := fmt.Sprintf("*<%s|%s>*\n%s", item.URL, item.MatchedName, p.YenString(item.Price))
+			if detail := n.Detail(item); detail != "" {
+				text += " · " + detail
+			}
+
+			block := slackBlock{
+				Type: "section",
+				Text: &slackText{Type: "mrkdwn", Text: text},
+			}
+			if item.ImageURL != "" {
+				block.Accessory = &slackAccessory{Type: "image", ImageURL: item.ImageURL, AltText: item.MatchedName}
+			}
+			blocks = append(blocks, block)
+		}
+
+		if err := postJSON(ctx, s.client, target, slackMessage{Text: n.Title, Blocks: blocks}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/drifterz13/dino-noti/model"
+)
+
+// StdoutNotifier prints notifications as text instead of sending them, for
+// dry runs.
+type StdoutNotifier struct {
+	w io.Writer
+}
+
+func NewStdoutNotifier(w io.Writer) *StdoutNotifier {
+	if w == nil {
+		w = os.Stdout
+	}
+	return &StdoutNotifier{w: w}
+}
+
+func (s *StdoutNotifier) Notify(ctx context.Context, target string, n model.Notification) error {
+	_, err := fmt.Fprintf(s.w, "--- To %s ---\n%s\n", target, RenderText(n))
+	return err
+}
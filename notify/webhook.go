@@ -0,0 +1,63 @@
+package notify
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/drifterz13/dino-noti/model"
+)
+
+// WebhookNotifier posts notifications as plain JSON to any URL.
+type WebhookNotifier struct {
+	client *http.Client
+}
+
+func NewWebhookNotifier(client *http.Client) *WebhookNotifier {
+	if client == nil {
+		client = defaultHTTPClient
+	}
+	return &WebhookNotifier{client: client}
+}
+
+type webhookPayload struct {
+	Language string        `json:"language"`
+	Title    string        `json:"title"`
+	SentAt   time.Time     `json:"sent_at"`
+	Items    []webhookItem `json:"items"`
+}
+
+type webhookItem struct {
+	AuctionID         string  `json:"auction_id"`
+	URL               string  `json:"url"`
+	Name              string  `json:"name"`
+	Model             string  `json:"model"`
+	Price             string  `json:"price"`
+	ImageURL          string  `json:"image_url"`
+	MarketPrice       int     `json:"market_price,omitempty"`
+	MarketDiffPercent float64 `json:"market_diff_percent,omitempty"`
+	Detail            string  `json:"detail,omitempty"`
+}
+
+func (w *WebhookNotifier) Notify(ctx context.Context, target string, n model.Notification) error {
+	payload := webhookPayload{
+		Language: n.Language,
+		Title:    n.Title,
+		SentAt:   time.Now(),
+		Items:    []webhookItem{},
+	}
+	for _, item := range n.Items {
+		payload.Items = append(payload.Items, webhookItem{
+			AuctionID:         item.AuctionID,
+			URL:               item.URL,
+			Name:              item.OriginalName,
+			Model:             item.MatchedName,
+			Price:             item.Price,
+			ImageURL:          item.ImageURL,
+			MarketPrice:       item.MarketPrice,
+			MarketDiffPercent: item.MarketDiffPercent,
+			Detail:            n.Detail(item),
+		})
+	}
+	return postJSON(ctx, w.client, target, payload)
+}
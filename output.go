@@ -0,0 +1,182 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/drifterz13/dino-noti/model"
+)
+
+var outputFormats = []string{"table", "json", "csv"}
+
+func checkFormat(format string) error {
+	for _, f := range outputFormats {
+		if f == format {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid format %q: use %s", format, strings.Join(outputFormats, ", "))
+}
+
+// scrapeItem is the JSON form of a scraped listing, which match reads back.
+type scrapeItem struct {
+	AuctionID    string     `json:"auction_id"`
+	URL          string     `json:"url"`
+	Name         string     `json:"name"`
+	Price        string     `json:"price"`
+	ImageURL     string     `json:"image_url"`
+	BuyoutPrice  string     `json:"buyout_price,omitempty"`
+	Bids         int        `json:"bids,omitempty"`
+	EndsAt       *time.Time `json:"ends_at,omitempty"`
+	Seller       string     `json:"seller,omitempty"`
+	SellerRating string     `json:"seller_rating,omitempty"`
+}
+
+func newScrapeItem(item model.ScrapeItem) scrapeItem {
+	out := scrapeItem{
+		AuctionID:    item.AuctionID,
+		URL:          item.URL,
+		Name:         item.Name,
+		Price:        item.Price,
+		ImageURL:     item.ImageURL,
+		BuyoutPrice:  item.Auction.BuyoutPrice,
+		Bids:         item.Auction.Bids,
+		Seller:       item.Auction.Seller,
+		SellerRating: item.Auction.SellerRating,
+	}
+	if !item.Auction.EndsAt.IsZero() {
+		out.EndsAt = &item.Auction.EndsAt
+	}
+	return out
+}
+
+func (item scrapeItem) model() model.ScrapeItem {
+	out := model.ScrapeItem{
+		AuctionID: item.AuctionID,
+		URL:       item.URL,
+		Name:      item.Name,
+		Price:     item.Price,
+		ImageURL:  item.ImageURL,
+		Auction: model.AuctionDetails{
+			BuyoutPrice:  item.BuyoutPrice,
+			Bids:         item.Bids,
+			Seller:       item.Seller,
+			SellerRating: item.SellerRating,
+		},
+	}
+	if item.EndsAt != nil {
+		out.Auction.EndsAt = *item.EndsAt
+	}
+	return out
+}
+
+type matchedItem struct {
+	AuctionID         string  `json:"auction_id"`
+	URL               string  `json:"url"`
+	Name              string  `json:"name"`
+	Model             string  `json:"model"`
+	Price             string  `json:"price"`
+	ImageURL          string  `json:"image_url"`
+	MarketPrice       int     `json:"market_price,omitempty"`
+	MarketDiffPercent float64 `json:"market_diff_percent,omitempty"`
+	PriceTHB          int     `json:"price_thb,omitempty"`
+	LandedTHB         int     `json:"landed_thb,omitempty"`
+}
+
+func newMatchedItem(item model.MatchedItem) matchedItem {
+	return matchedItem{
+		AuctionID:         item.AuctionID,
+		URL:               item.URL,
+		Name:              item.OriginalName,
+		Model:             item.MatchedName,
+		Price:             item.Price,
+		ImageURL:          item.ImageURL,
+		MarketPrice:       item.MarketPrice,
+		MarketDiffPercent: item.MarketDiffPercent,
+		PriceTHB:          item.PriceTHB,
+		LandedTHB:         item.LandedTHB,
+	}
+}
+
+type watchEntry struct {
+	SourceID  string    `json:"source_id"`
+	Model     string    `json:"model"`
+	AddedBy   string    `json:"added_by,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func newWatchEntry(e model.WatchEntry) watchEntry {
+	addedBy := e.AddedByName
+	if addedBy == "" {
+		addedBy = e.AddedBy
+	}
+	return watchEntry{SourceID: e.SourceID, Model: e.Model, AddedBy: addedBy, CreatedAt: e.CreatedAt}
+}
+
+// table is the tabular form of command output, written as an aligned table
+// or CSV. JSON output uses the item types instead.
+type table struct {
+	header []string
+	rows   [][]string
+}
+
+func scrapeItemsTable(items []scrapeItem) table {
+	t := table{header: []string{"auction_id", "price", "bids", "ends_at", "name", "url"}}
+	for _, item := range items {
+		endsAt := ""
+		if item.EndsAt != nil {
+			endsAt = item.EndsAt.Format(time.DateTime)
+		}
+		t.rows = append(t.rows, []string{item.AuctionID, item.Price, strconv.Itoa(item.Bids), endsAt, item.Name, item.URL})
+	}
+	return t
+}
+
+func matchedItemsTable(items []matchedItem) table {
+	t := table{header: []string{"auction_id", "model", "price", "market_price", "market_diff_percent", "name", "url"}}
+	for _, item := range items {
+		marketPrice, diff := "", ""
+		if item.MarketPrice > 0 {
+			marketPrice = strconv.Itoa(item.MarketPrice)
+			diff = strconv.FormatFloat(item.MarketDiffPercent, 'f', 1, 64)
+		}
+		t.rows = append(t.rows, []string{item.AuctionID, item.Model, item.Price, marketPrice, diff, item.Name, item.URL})
+	}
+	return t
+}
+
+func watchEntriesTable(entries []watchEntry) table {
+	t := table{header: []string{"source_id", "model", "added_by", "created_at"}}
+	for _, e := range entries {
+		t.rows = append(t.rows, []string{e.SourceID, e.Model, e.AddedBy, e.CreatedAt.Format(time.DateTime)})
+	}
+	return t
+}
+
+// writeOutput writes v as JSON, or its table as CSV or an aligned table.
+func writeOutput(w io.Writer, format string, v any, t table) error {
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(v)
+	case "csv":
+		writer := csv.NewWriter(w)
+		writer.Write(t.header)
+		writer.WriteAll(t.rows)
+		return writer.Error()
+	default:
+		writer := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(writer, strings.ToUpper(strings.Join(t.header, "\t")))
+		for _, row := range t.rows {
+			fmt.Fprintln(writer, strings.Join(row, "\t"))
+		}
+		return writer.Flush()
+	}
+}
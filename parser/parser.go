@@ -1,20 +1,34 @@
 package parser
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
+	"net/url"
+	"path"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/drifterz13/dino-noti/model"
 )
 
+const (
+	itemSelector  = ".itemCard"
+	nameSelector  = ".itemCard__itemName a"
+	priceSelector = ".g-priceDetails__item .g-price"
+	imageSelector = ".g-thumbnail__image"
+)
+
 type BuyeeParser struct{}
 
 func NewBuyeeParser() *BuyeeParser {
 	return &BuyeeParser{}
 }
 
-func (p *BuyeeParser) Parse(htmlContent string) ([]model.ScrapeItem, error) {
+func (p *BuyeeParser) Parse(ctx context.Context, htmlContent string) ([]model.ScrapeItem, error) {
 	doc, err := goquery.NewDocumentFromReader(strings.NewReader(htmlContent))
 	if err != nil {
 		return nil, fmt.Errorf("failed to load HTML for parsing: %w", err)
@@ -22,25 +36,23 @@ func (p *BuyeeParser) Parse(htmlContent string) ([]model.ScrapeItem, error) {
 
 	var items []model.ScrapeItem
 
-	itemSelector := ".itemCard"
-
 	doc.Find(itemSelector).Each(func(i int, s *goquery.Selection) {
-		name := strings.TrimSpace(s.Find(".itemCard__itemName a").Text())
+		name := strings.TrimSpace(s.Find(nameSelector).Text())
 
-		url, exists := s.Find(".itemCard__itemName a").Attr("href")
-		if !exists {
+		url, urlExists := s.Find(nameSelector).Attr("href")
+		if !urlExists {
 			// Try another selector if the first one doesn't work
-			url, exists = s.Find(".g-thumbnail__outer a").Attr("href")
+			url, urlExists = s.Find(".g-thumbnail__outer a").Attr("href")
 		}
 
 		// Find the current price - first price in the list
-		priceText := s.Find(".g-priceDetails__item .g-price").First().Text()
+		priceText := s.Find(priceSelector).First().Text()
 		// Clean up the price (remove "yen" and trim spaces)
 		price := strings.TrimSpace(strings.Replace(priceText, "yen", "", -1))
 
 		// Find the image URL
-		imageURL, exists := s.Find(".g-thumbnail__image").Attr("data-src")
-		if exists {
+		imageURL, imageExists := s.Find(imageSelector).Attr("data-src")
+		if imageExists {
 			fileExt := ".jpg"
 			fileExtIdx := strings.Index(imageURL, fileExt)
 
@@ -50,7 +62,7 @@ func (p *BuyeeParser) Parse(htmlContent string) ([]model.ScrapeItem, error) {
 		}
 
 		// Only add items that have at least a name and URL
-		if name != "" && exists {
+		if name != "" && urlExists {
 			// Handle relative URLs by prepending the base URL if needed
 			baseURL := "https://buyee.jp"
 			if !strings.HasPrefix(url, "http") {
@@ -58,19 +70,130 @@ func (p *BuyeeParser) Parse(htmlContent string) ([]model.ScrapeItem, error) {
 			}
 
 			items = append(items, model.ScrapeItem{
-				Name:     name,
-				Price:    price,
-				URL:      url,
-				ImageURL: imageURL,
+				AuctionID: auctionIDFromURL(url),
+				Name:      name,
+				Price:     price,
+				URL:       url,
+				ImageURL:  imageURL,
+				Auction:   parseAuctionDetails(s, time.Now()),
 			})
 		}
 	})
 
 	if len(items) == 0 {
-		fmt.Println("Warning: No items found with selector:", itemSelector)
+		slog.WarnContext(ctx, "No items found", "selector", itemSelector)
 	} else {
-		fmt.Printf("Found %d items on the page.\n", len(items))
+		slog.DebugContext(ctx, "Parsed page", "items", len(items))
 	}
 
 	return items, nil
 }
+
+// SelectorCount is how many item cards on a page a selector matched in.
+type SelectorCount struct {
+	Selector string
+	Matches  int
+}
+
+// CheckSelectors counts the item cards on a page and, for each field the
+// parser reads, the cards it was found in. A markup change shows up as a
+// field missing from most cards, or no cards at all.
+func CheckSelectors(htmlContent string) ([]SelectorCount, error) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(htmlContent))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load HTML for parsing: %w", err)
+	}
+
+	cards := doc.Find(itemSelector)
+	counts := []SelectorCount{{Selector: itemSelector, Matches: cards.Length()}}
+	for _, selector := range []string{nameSelector, priceSelector, imageSelector} {
+		count := SelectorCount{Selector: selector}
+		cards.Each(func(_ int, s *goquery.Selection) {
+			if s.Find(selector).Length() > 0 {
+				count.Matches++
+			}
+		})
+		counts = append(counts, count)
+	}
+	return counts, nil
+}
+
+// auctionIDFromURL extracts the auction ID, e.g. "x1193046789" from
+// https://buyee.jp/item/yahoo/auction/x1193046789?conversionType=...
+func auctionIDFromURL(itemURL string) string {
+	u, err := url.Parse(itemURL)
+	if err != nil {
+		return itemURL
+	}
+	return path.Base(u.Path)
+}
+
+var (
+	durationPattern = regexp.MustCompile(`(\d+)\s*(days?|day\(s\)|日|hours?|hour\(s\)|時間|minutes?|mins?|minute\(s\)|分)`)
+	digitsPattern   = regexp.MustCompile(`\d+`)
+)
+
+// parseAuctionDetails reads the labelled fields of an item card, e.g.
+// "Number of Bids" or "Time Remaining". Labels the card does not show leave
+// their field empty.
+func parseAuctionDetails(s *goquery.Selection, now time.Time) model.AuctionDetails {
+	var details model.AuctionDetails
+
+	s.Find(".g-title").Each(func(i int, title *goquery.Selection) {
+		label := strings.ToLower(strings.TrimSpace(title.Text()))
+		value := strings.TrimSpace(title.Parent().Find(".g-text, .g-price").First().Text())
+		if value == "" {
+			return
+		}
+
+		switch {
+		case containsAny(label, "buyout", "buy-it-now", "buy it now", "即決"):
+			details.BuyoutPrice = strings.TrimSpace(strings.Replace(value, "yen", "", -1))
+		case containsAny(label, "bids", "入札"):
+			if n, err := strconv.Atoi(digitsPattern.FindString(value)); err == nil {
+				details.Bids = n
+			}
+		case containsAny(label, "time remaining", "time left", "残り時間"):
+			if d, ok := parseTimeLeft(value); ok {
+				details.EndsAt = now.Add(d)
+			}
+		case containsAny(label, "rating", "評価"):
+			details.SellerRating = value
+		case containsAny(label, "seller", "出品者"):
+			details.Seller = value
+		}
+	})
+
+	return details
+}
+
+// parseTimeLeft understands the remaining time as Buyee shows it, e.g.
+// "2 days", "5 hours 10 minutes" or "3日".
+func parseTimeLeft(text string) (time.Duration, bool) {
+	var total time.Duration
+	matches := durationPattern.FindAllStringSubmatch(strings.ToLower(text), -1)
+	for _, m := range matches {
+		n, err := strconv.Atoi(m[1])
+		if err != nil {
+			return 0, false
+		}
+		switch unit := m[2]; {
+		case strings.HasPrefix(unit, "day"), unit == "日":
+			total += time.Duration(n) * 24 * time.Hour
+		case strings.HasPrefix(unit, "hour"), unit == "時間":
+			total += time.Duration(n) * time.Hour
+		default:
+			total += time.Duration(n) * time.Minute
+		}
+	}
+	return total, len(matches) > 0
+}
+
+func containsAny(s string, substrs ...string) bool {
+	for _, substr := range substrs {
+		if strings.Contains(s, substr) {
+			return true
+		}
+	}
+	return false
+}
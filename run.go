@@ -0,0 +1,114 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/drifterz13/dino-noti/config"
+	"github.com/drifterz13/dino-noti/model"
+	"github.com/drifterz13/dino-noti/notify"
+	"github.com/drifterz13/dino-noti/service"
+	"github.com/drifterz13/dino-noti/store"
+)
+
+func runOnce(args []string) error {
+	flags := flag.NewFlagSet("run", flag.ExitOnError)
+	dryRun := flags.Bool("dry-run", false, "work on a copy of the database and print notifications to stdout instead of sending them, or to stderr with json or csv output")
+	format := flags.String("format", "table", "output `format` of the matched items: table, json or csv")
+	flags.Usage = usageFor(flags, "run [flags]", "Run the full pipeline once: scrape, match, record and notify.")
+	flags.Parse(args)
+
+	if err := checkFormat(*format); err != nil {
+		return err
+	}
+
+	required := []config.Requirement{config.RequireGemini}
+	if !*dryRun {
+		required = append(required, config.RequireLine)
+	}
+	cfg, err := loadConfig(required...)
+	if err != nil {
+		return err
+	}
+
+	stopTracing, err := startTracing(cfg)
+	if err != nil {
+		return err
+	}
+	defer stopTracing()
+
+	st, err := store.NewStore(cfg.DatabasePath)
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	if *dryRun {
+		dir, err := os.MkdirTemp("", "dino-noti-dry-run")
+		if err != nil {
+			st.Close()
+			return fmt.Errorf("failed to create temporary directory: %w", err)
+		}
+		defer os.RemoveAll(dir)
+
+		if st, err = snapshotStore(st, filepath.Join(dir, "dino-noti.db")); err != nil {
+			return err
+		}
+		fmt.Fprintln(os.Stderr, "Dry run: working on a copy of the database")
+	}
+	defer st.Close()
+
+	srv := service.NewService(cfg, st)
+	if *dryRun {
+		// JSON and CSV results must stay parseable, so the notifications
+		// printed next to them go to stderr.
+		out := os.Stdout
+		if *format != "table" {
+			out = os.Stderr
+		}
+		srv.RedirectNotifications(notify.NewStdoutNotifier(out))
+	}
+
+	ctx, stop := commandContext()
+	defer stop()
+
+	job, err := srv.RunJob(ctx, model.TriggerCLI)
+	if err != nil {
+		return err
+	}
+
+	items := []matchedItem{}
+	for _, item := range job.Checkpoint.Matched {
+		items = append(items, newMatchedItem(item))
+	}
+	if err := writeOutput(os.Stdout, *format, items, matchedItemsTable(items)); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Run %d: %d pages, %d items, %d matches, %d deals, %d price drops\n",
+		job.RunID, job.Checkpoint.PagesScraped, len(job.Checkpoint.Scraped), len(job.Checkpoint.Matched),
+		len(job.Checkpoint.Deals), len(job.Checkpoint.Drops))
+
+	switch {
+	case job.Status == model.JobDone:
+		return nil
+	case job.Status == model.JobQueued && !*dryRun:
+		return fmt.Errorf("job %d failed in the %s stage and is queued for a retry: %s", job.ID, job.Stage, job.LastError)
+	default:
+		return fmt.Errorf("job %d failed in the %s stage: %s", job.ID, job.Stage, job.LastError)
+	}
+}
+
+// snapshotStore closes st and opens a copy of it at path instead, so a dry
+// run neither records listings nor marks them as notified.
+func snapshotStore(st *store.Store, path string) (*store.Store, error) {
+	defer st.Close()
+
+	if err := st.Snapshot(path); err != nil {
+		return nil, err
+	}
+	snapshot, err := store.NewStore(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database copy: %w", err)
+	}
+	return snapshot, nil
+}
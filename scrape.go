@@ -0,0 +1,68 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/drifterz13/dino-noti/model"
+)
+
+func scrape(args []string) error {
+	flags := flag.NewFlagSet("scrape", flag.ExitOnError)
+	target := flags.String("target", "", "target `ID, name or URL` to scrape (default every enabled target)")
+	pages := flags.Int("pages", 0, "scrape at most `N` pages per target (default the target's own limit)")
+	format := flags.String("format", "table", "output `format`: table, json or csv")
+	flags.Usage = usageFor(flags, "scrape [flags]", "Scrape search pages and print the listings found, without matching or recording them.")
+	flags.Parse(args)
+
+	if err := checkFormat(*format); err != nil {
+		return err
+	}
+	if *pages < 0 {
+		return fmt.Errorf("invalid -pages %d", *pages)
+	}
+
+	srv, close, err := openService()
+	if err != nil {
+		return err
+	}
+	defer close()
+
+	var targets []model.Target
+	if *target != "" {
+		t, err := srv.ResolveTarget(*target)
+		if err != nil {
+			return err
+		}
+		targets = []model.Target{t}
+	} else if targets, err = srv.ScrapeTargets(); err != nil {
+		return err
+	}
+	if *pages > 0 {
+		for i := range targets {
+			targets[i].MaxPages = *pages
+		}
+	}
+
+	ctx, stop := commandContext()
+	defer stop()
+
+	scrapedItems, pagesScraped, scrapeErrors := srv.Scrape(ctx, targets)
+	for _, err := range scrapeErrors {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	}
+
+	items := []scrapeItem{}
+	for _, item := range scrapedItems {
+		items = append(items, newScrapeItem(item))
+	}
+	if err := writeOutput(os.Stdout, *format, items, scrapeItemsTable(items)); err != nil {
+		return err
+	}
+
+	if pagesScraped == 0 && len(scrapeErrors) > 0 {
+		return fmt.Errorf("failed to scrape any page")
+	}
+	return nil
+}
@@ -1,21 +1,33 @@
 package scraper
 
 import (
+	"context"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"time"
 
 	"github.com/drifterz13/dino-noti/model"
 )
 
-func FetchPage(url string) (string, error) {
-	fmt.Printf("Fetching URL: %s\n", url)
+// StatusError is returned by FetchPage when the server answers with anything
+// but 200 OK.
+type StatusError struct {
+	Code int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("received non-200 status code: %d", e.Code)
+}
+
+func FetchPage(ctx context.Context, url string) (string, error) {
+	slog.DebugContext(ctx, "Fetching page", "url", url)
 	client := http.Client{
 		Timeout: 10 * time.Second,
 	}
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return "", fmt.Errorf("failed to create request: %w", err)
 	}
@@ -29,7 +41,7 @@ func FetchPage(url string) (string, error) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("received non-200 status code: %d", resp.StatusCode)
+		return "", &StatusError{Code: resp.StatusCode}
 	}
 
 	bodyBytes, err := io.ReadAll(resp.Body)
@@ -41,13 +53,5 @@ func FetchPage(url string) (string, error) {
 }
 
 type Parser interface {
-	Parse(htmlContent string) ([]model.ScrapeItem, error)
-}
-
-func ScrapePage(url string, parser Parser) ([]model.ScrapeItem, error) {
-	htmlContent, err := FetchPage(url)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch and parse %s: %w", url, err)
-	}
-	return parser.Parse(htmlContent)
+	Parse(ctx context.Context, htmlContent string) ([]model.ScrapeItem, error)
 }
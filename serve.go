@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/drifterz13/dino-noti/api"
+	"github.com/drifterz13/dino-noti/config"
+	"github.com/drifterz13/dino-noti/dashboard"
+	"github.com/drifterz13/dino-noti/feed"
+	"github.com/drifterz13/dino-noti/health"
+	"github.com/drifterz13/dino-noti/line"
+	"github.com/drifterz13/dino-noti/metrics"
+	"github.com/drifterz13/dino-noti/service"
+	"github.com/drifterz13/dino-noti/store"
+)
+
+func serve(args []string) error {
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	flags.Usage = usageFor(flags, "serve", "Run the LINE bot, scheduler, workers, admin API, dashboard, metrics and health checks.")
+	flags.Parse(args)
+
+	cfg, err := loadConfig(config.RequireGemini, config.RequireLine)
+	if err != nil {
+		return err
+	}
+
+	stopTracing, err := startTracing(cfg)
+	if err != nil {
+		return err
+	}
+	defer stopTracing()
+
+	st, err := store.NewStore(cfg.DatabasePath)
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer st.Close()
+
+	srv := service.NewService(cfg, st)
+	srv.StartWorkers()
+	srv.StartScheduler()
+	srv.StartDigests()
+
+	port := os.Getenv("PORT")
+	if port == "" {
+		port = "8000"
+	}
+	http.HandleFunc("/callback", srv.HandleLineMessageReq)
+	if cfg.AdminToken != "" {
+		http.Handle("/api/", api.NewHandler(srv, cfg.AdminToken))
+	} else {
+		slog.Info("ADMIN_TOKEN not set, the admin API is disabled")
+	}
+	if cfg.DashboardToken != "" {
+		location, err := time.LoadLocation(cfg.Timezone)
+		if err != nil {
+			return fmt.Errorf("failed to load time zone: %w", err)
+		}
+		dash, err := dashboard.NewHandler(srv, cfg.DashboardToken, location)
+		if err != nil {
+			return fmt.Errorf("failed to load dashboard: %w", err)
+		}
+		http.Handle("/dashboard/", dash)
+	} else {
+		slog.Info("DASHBOARD_TOKEN not set, the dashboard is disabled")
+	}
+	http.Handle("/feeds/", feed.NewHandler(srv))
+	http.HandleFunc("GET "+config.PLACEHOLDER_IMAGE_PATH, line.ServePlaceholderImage)
+	http.Handle("GET /metrics", metrics.Handler())
+	healthHandler := health.NewHandler(srv, cfg.AdminToken)
+	http.Handle("GET /healthz", healthHandler)
+	http.Handle("GET /readyz", healthHandler)
+	http.Handle("GET /diagnostics", healthHandler)
+	server := &http.Server{Addr: ":" + port}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	serverErr := make(chan error, 1)
+	go func() {
+		slog.Info("Listening", "port", port)
+		serverErr <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serverErr:
+		return fmt.Errorf("HTTP server error: %w", err)
+	case <-ctx.Done():
+	}
+	stop()
+
+	slog.Info("Shutting down, waiting for in-flight work", "timeout", cfg.ShutdownTimeout)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+
+	// Stop accepting webhooks first, so no new work starts while waiting.
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		slog.Error("Error shutting down HTTP server", "err", err)
+	}
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		slog.Error("Error shutting down", "err", err)
+	}
+	slog.Info("Shutdown complete")
+	return nil
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/drifterz13/dino-noti/i18n"
+	"github.com/drifterz13/dino-noti/line"
+	"github.com/drifterz13/dino-noti/model"
+)
+
+// minBaselineRuns is how many healthy runs the item count is compared
+// against before a drop counts as an anomaly.
+const minBaselineRuns = 3
+
+// detectAnomalies reports what looks wrong with a scrape: no items on the
+// pages that loaded, far fewer items than recent runs found, many items
+// without a price or image, or repeated non-200 responses. These usually mean
+// Buyee changed its markup or started blocking the scraper, which would
+// otherwise look like a run without matches.
+func (srv *Service) detectAnomalies(ctx context.Context, checkpoint model.JobCheckpoint) []string {
+	var anomalies []string
+
+	if checkpoint.BadStatuses >= srv.cfg.AnomalyMaxBadStatuses {
+		anomalies = append(anomalies, fmt.Sprintf("%d pages answered with a non-200 status", checkpoint.BadStatuses))
+	}
+	if checkpoint.PagesScraped == 0 {
+		return anomalies
+	}
+
+	items := checkpoint.Scraped
+	if len(items) == 0 {
+		return append(anomalies, fmt.Sprintf("no items found on %d scraped pages", checkpoint.PagesScraped))
+	}
+
+	counts, err := srv.store.HealthyItemCounts(srv.cfg.AnomalyWindow)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error loading recent item counts", "err", err)
+	} else if len(counts) >= min(minBaselineRuns, srv.cfg.AnomalyWindow) {
+		total := 0
+		for _, count := range counts {
+			total += count
+		}
+		average := float64(total) / float64(len(counts))
+		if float64(len(items)) < average*(1-srv.cfg.AnomalyDropPercent/100) {
+			anomalies = append(anomalies, fmt.Sprintf("found %d items, down from an average of %.0f over the last %d runs",
+				len(items), average, len(counts)))
+		}
+	}
+
+	missingPrices, missingImages := 0, 0
+	for _, item := range items {
+		if strings.TrimSpace(item.Price) == "" {
+			missingPrices++
+		}
+		if strings.TrimSpace(item.ImageURL) == "" {
+			missingImages++
+		}
+	}
+	for _, missing := range []struct {
+		count int
+		what  string
+	}{
+		{missingPrices, "price"},
+		{missingImages, "image"},
+	} {
+		percent := float64(missing.count) * 100 / float64(len(items))
+		if missing.count > 0 && percent > srv.cfg.AnomalyMissingPercent {
+			anomalies = append(anomalies, fmt.Sprintf("%d of %d items (%.0f%%) have no %s",
+				missing.count, len(items), percent, missing.what))
+		}
+	}
+
+	return anomalies
+}
+
+// alertRunHealth tells the alert chat when runs become degraded and when
+// they recover, rather than on every degraded run, so a broken page layout
+// does not page the admin every schedule interval. The alert is written in
+// the alert chat's language. Without an alert chat, or when notifications
+// are redirected, the alerts are only logged.
+func (srv *Service) alertRunHealth(ctx context.Context, run *model.Run) {
+	if run.Degraded() {
+		slog.WarnContext(ctx, "Run degraded", "anomalies", run.Anomalies)
+	}
+
+	previous, err := srv.store.PreviousRun(run.ID)
+	switch {
+	case errors.Is(err, ErrNotFound):
+		previous = &model.Run{}
+	case err != nil:
+		slog.ErrorContext(ctx, "Error loading previous run", "err", err)
+		return
+	}
+	if previous.Degraded() == run.Degraded() {
+		return
+	}
+
+	var prefs model.Preferences
+	if sub, err := srv.store.Subscriber(srv.cfg.AlertLineID); err == nil {
+		prefs = sub.Preferences
+	}
+	p := srv.printer(prefs)
+
+	var text strings.Builder
+	if run.Degraded() {
+		text.WriteString(p.T(i18n.RunDegraded, run.ID, run.Trigger))
+		for _, anomaly := range run.Anomalies {
+			fmt.Fprintf(&text, "\n• %s", anomaly)
+		}
+		if len(run.Errors) > 0 {
+			text.WriteString("\n\n" + p.T(i18n.RunErrors, len(run.Errors), run.Errors[0]))
+		}
+		text.WriteString("\n\n" + p.T(i18n.RunDegradedFooter))
+	} else {
+		text.WriteString(p.T(i18n.RunRecovered, run.ID, run.Trigger, run.ItemsFound, run.PagesScraped))
+	}
+	if srv.cfg.AlertLineID == "" || srv.redirect != nil {
+		slog.InfoContext(ctx, "Not sending run health alert", "alert", text.String())
+		return
+	}
+
+	lineBotClient, err := line.NewLineBotClient(ctx, srv.cfg)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error creating LINE Bot client", "err", err)
+		return
+	}
+	if err := lineBotClient.PushMessages(srv.cfg.AlertLineID, line.TextMessages(text.String())...); err != nil {
+		slog.ErrorContext(ctx, "Error sending run health alert", "err", err)
+	}
+}
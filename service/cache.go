@@ -0,0 +1,48 @@
+package service
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/drifterz13/dino-noti/model"
+)
+
+type runResult struct {
+	run   *model.Run
+	items []model.MatchedItem
+}
+
+// resultCache keeps the results of the last finished run, served to searches
+// within the freshness window instead of queueing another run.
+type resultCache struct {
+	mu   sync.Mutex
+	last *runResult
+	at   time.Time
+}
+
+// get returns the last result if it is younger than maxAge.
+func (c *resultCache) get(maxAge time.Duration) (runResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.last == nil || maxAge <= 0 || time.Since(c.at) >= maxAge {
+		return runResult{}, false
+	}
+	return c.last.clone(), true
+}
+
+func (c *resultCache) put(result runResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.last = &result
+	c.at = time.Now()
+}
+
+// clone gives every requester its own items, since replies sort and filter
+// them in place.
+func (r runResult) clone() runResult {
+	r.items = slices.Clone(r.items)
+	return r
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"fmt"
+	"net/mail"
+	"slices"
+	"strings"
+
+	"github.com/drifterz13/dino-noti/i18n"
+	"github.com/drifterz13/dino-noti/model"
+	"github.com/drifterz13/dino-noti/notify"
+)
+
+// handleChannels lists and edits the channels deal and price drop alerts are
+// sent to. LINE always targets the current chat.
+func (srv *Service) handleChannels(req *commandRequest) error {
+	fields := strings.Fields(req.args)
+	if len(fields) == 0 || strings.EqualFold(fields[0], "list") {
+		return srv.replyChannels(req)
+	}
+	if len(fields) < 2 || len(fields) > 3 {
+		return srv.reply(req, req.printer.T(i18n.ChannelsUsage))
+	}
+
+	channel := model.Channel(strings.ToLower(fields[1]))
+	if !slices.Contains(model.Channels, channel) {
+		return srv.reply(req, req.printer.T(i18n.UnknownChannel, fields[1])+"\n"+req.printer.T(i18n.ChannelsUsage))
+	}
+	target := ""
+	if len(fields) == 3 {
+		target = fields[2]
+	}
+
+	switch strings.ToLower(fields[0]) {
+	case "add":
+		return srv.addChannel(req, channel, target)
+	case "remove":
+		removed, err := srv.store.RemoveRoutes(req.sourceID, channel, target)
+		if err != nil {
+			return err
+		}
+		if removed == 0 {
+			return srv.reply(req, req.printer.T(i18n.NoChannelToRemove, channel))
+		}
+		return srv.replyChannels(req)
+	default:
+		return srv.reply(req, req.printer.T(i18n.ChannelsUsage))
+	}
+}
+
+func (srv *Service) addChannel(req *commandRequest, channel model.Channel, target string) error {
+	if channel == model.ChannelLine {
+		target = req.sourceID
+	}
+	target, problem := parseTarget(req.printer, channel, target)
+	if problem != "" {
+		return srv.reply(req, problem+"\n"+req.printer.T(i18n.ChannelsUsage))
+	}
+
+	// Alerts go to the chat itself until a channel is configured, so keep it
+	// when the first other channel is added.
+	routes, err := srv.store.Routes(req.sourceID)
+	if err != nil {
+		return err
+	}
+	if len(routes) == 0 && channel != model.ChannelLine {
+		if _, err := srv.store.AddRoute(req.sourceID, model.Route{Channel: model.ChannelLine, Target: req.sourceID}); err != nil {
+			return err
+		}
+	}
+
+	if _, err := srv.store.AddRoute(req.sourceID, model.Route{Channel: channel, Target: target}); err != nil {
+		return err
+	}
+	return srv.replyChannels(req)
+}
+
+func (srv *Service) replyChannels(req *commandRequest) error {
+	routes, err := srv.routes(req.sourceID)
+	if err != nil {
+		return err
+	}
+
+	msg := strings.Builder{}
+	msg.WriteString(req.printer.T(i18n.ChannelsHeader) + "\n")
+	for idx, route := range routes {
+		target := route.Target
+		if route.Channel == model.ChannelLine && target == req.sourceID {
+			target = req.printer.T(i18n.ThisChat)
+		}
+		msg.WriteString(fmt.Sprintf("%d. %s - %s\n", idx+1, route.Channel, target))
+	}
+	return srv.reply(req, msg.String())
+}
+
+// parseTarget returns the channel target to store, reducing an email target
+// to its bare address, or describes what is wrong with it.
+func parseTarget(p *i18n.Printer, channel model.Channel, target string) (string, string) {
+	switch channel {
+	case model.ChannelDiscord, model.ChannelSlack, model.ChannelWebhook:
+		if err := notify.CheckWebhookURL(target); err != nil {
+			return "", p.T(i18n.ChannelNeedsURL, channel)
+		}
+	case model.ChannelEmail:
+		addr, err := mail.ParseAddress(target)
+		if err != nil {
+			return "", p.T(i18n.InvalidEmail, target)
+		}
+		return addr.Address, ""
+	}
+	return target, ""
+}
@@ -0,0 +1,255 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/drifterz13/dino-noti/command"
+	"github.com/drifterz13/dino-noti/i18n"
+	"github.com/drifterz13/dino-noti/line"
+	"github.com/drifterz13/dino-noti/market"
+	"github.com/drifterz13/dino-noti/model"
+)
+
+type commandRequest struct {
+	// ctx carries the event or job the request belongs to into the logs.
+	ctx        context.Context
+	client     *line.LineBotClient
+	replyToken string
+	receivedAt time.Time
+	// replied is set once the reply token has been used; LINE accepts it once.
+	replied    bool
+	sourceID   string
+	sourceKind model.SourceKind
+	// userID is the member behind a group or room event, if LINE shared it.
+	userID string
+	args   string
+	// printer and location render replies in the source's language and
+	// timezone.
+	printer  *i18n.Printer
+	location *time.Location
+}
+
+type commandHandler func(srv *Service, req *commandRequest) error
+
+var commandHandlers = map[string]commandHandler{
+	command.Search:   (*Service).handleSearch,
+	command.New:      (*Service).handleNew,
+	command.Watch:    (*Service).handleWatch,
+	command.Unwatch:  (*Service).handleUnwatch,
+	command.List:     (*Service).handleList,
+	command.Mute:     (*Service).handleMute,
+	command.Settings: (*Service).handleSettings,
+	command.Help:     (*Service).handleHelp,
+	command.History:  (*Service).handleHistory,
+	command.Diff:     (*Service).handleHistory,
+	command.Stats:    (*Service).handleStats,
+	command.Channels: (*Service).handleChannels,
+	command.Feed:     (*Service).handleFeed,
+}
+
+func (srv *Service) routeCommand(req *commandRequest, cmd command.Command) {
+	if cmd.Unknown {
+		if err := srv.reply(req, req.printer.T(i18n.UnknownCommand, cmd.Name)); err != nil {
+			slog.ErrorContext(req.ctx, "Error sending message", "err", err)
+		}
+		return
+	}
+
+	req.args = cmd.Args
+	if err := commandHandlers[cmd.Name](srv, req); err != nil {
+		slog.ErrorContext(req.ctx, "Error handling command", "command", cmd.Name, "err", err)
+	}
+}
+
+func (srv *Service) handleSearch(req *commandRequest) error {
+	return srv.search(req, false)
+}
+
+func (srv *Service) handleSearchCarousel(req *commandRequest) error {
+	return srv.search(req, true)
+}
+
+// search replies with the results of a recent enough run, or queues a run
+// whose results are pushed to the source once ready.
+func (srv *Service) search(req *commandRequest, asCarousel bool) error {
+	if result, ok := srv.results.get(srv.cfg.ResultFreshness); ok {
+		slog.InfoContext(req.ctx, "Serving results from cache", "cached_run_id", result.run.ID)
+		return srv.replyItems(req, result.items, asCarousel, result.run.Degraded())
+	}
+
+	srv.acknowledge(req)
+	_, err := srv.EnqueueRun(req.ctx, model.TriggerWebhook, &model.JobRequester{SourceID: req.sourceID, AsCarousel: asCarousel})
+	return err
+}
+
+// replyItems stores the visible items as a result set, best deals first, and
+// replies with its first page. When a degraded run left nothing to show, the
+// reply says the search did not work rather than that nothing matched.
+func (srv *Service) replyItems(req *commandRequest, items []model.MatchedItem, asCarousel, degraded bool) error {
+	items = srv.visibleItems(req.ctx, req.sourceID, items)
+	if len(items) == 0 && degraded {
+		return srv.reply(req, req.printer.T(i18n.SearchDegraded))
+	}
+	market.SortByDeal(items)
+
+	page := line.ItemPage{Items: items, AsCarousel: asCarousel, PlaceholderImageURL: srv.cfg.PlaceholderImageURL}
+	if len(items) > 0 {
+		id, err := srv.store.SaveResultSet(req.sourceID, items)
+		if err != nil {
+			return err
+		}
+		page.ResultSetID = id
+	}
+
+	return srv.send(req, line.ItemPageMessages(req.printer, page)...)
+}
+
+func (srv *Service) handleNew(req *commandRequest) error {
+	diff, err := srv.RunDiff()
+	if errors.Is(err, ErrNotEnoughRuns) {
+		return srv.reply(req, req.printer.T(i18n.NotEnoughRuns))
+	}
+	if err != nil {
+		return err
+	}
+
+	appeared := srv.visibleItems(req.ctx, req.sourceID, diff.Appeared)
+	if len(appeared) == 0 {
+		return srv.reply(req, req.printer.T(i18n.NoNewItems))
+	}
+	return srv.reply(req, line.GenerateNewItemsMessage(req.printer, appeared))
+}
+
+func (srv *Service) handleWatch(req *commandRequest) error {
+	if req.args == "" {
+		return srv.reply(req, req.printer.T(i18n.WatchUsage))
+	}
+
+	entry := model.WatchEntry{SourceID: req.sourceID, Model: req.args, AddedBy: req.userID}
+	if req.sourceKind != model.SourceUser && req.userID != "" {
+		displayName, err := req.client.DisplayName(line.Source{ID: req.sourceID, Kind: req.sourceKind, UserID: req.userID})
+		if err != nil {
+			slog.WarnContext(req.ctx, "Error looking up display name", "err", err)
+		}
+		entry.AddedByName = displayName
+	}
+
+	entry, added, err := srv.Watch(entry)
+	if err != nil {
+		return err
+	}
+	if !added {
+		return srv.reply(req, req.printer.T(i18n.AlreadyWatching, entry.Model))
+	}
+	if entry.AddedByName != "" {
+		return srv.reply(req, req.printer.T(i18n.MemberWatching, entry.AddedByName, entry.Model))
+	}
+	return srv.reply(req, req.printer.T(i18n.Watching, entry.Model))
+}
+
+func (srv *Service) handleUnwatch(req *commandRequest) error {
+	if req.args == "" {
+		return srv.reply(req, req.printer.T(i18n.UnwatchUsage))
+	}
+
+	removed, err := srv.Unwatch(req.sourceID, req.args)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return srv.reply(req, req.printer.T(i18n.NotWatching, req.args))
+	}
+	return srv.reply(req, req.printer.T(i18n.StoppedWatching, req.args))
+}
+
+func (srv *Service) handleList(req *commandRequest) error {
+	watchlist, err := srv.Watchlist(req.sourceID)
+	if err != nil {
+		return err
+	}
+	tracked, err := srv.store.TrackedListings(req.sourceID)
+	if err != nil {
+		return err
+	}
+
+	msg := strings.Builder{}
+	if len(watchlist) == 0 {
+		msg.WriteString(req.printer.T(i18n.WatchlistEmpty) + "\n")
+	} else {
+		if req.sourceKind == model.SourceUser {
+			msg.WriteString(req.printer.T(i18n.WatchlistUser) + "\n")
+		} else {
+			msg.WriteString(req.printer.T(i18n.WatchlistGroup) + "\n")
+		}
+		for idx, entry := range watchlist {
+			if entry.AddedByName != "" {
+				msg.WriteString(fmt.Sprintf("%d. %s (@%s)\n", idx+1, entry.Model, entry.AddedByName))
+				continue
+			}
+			msg.WriteString(fmt.Sprintf("%d. %s\n", idx+1, entry.Model))
+		}
+	}
+	msg.WriteString("\n" + req.printer.T(i18n.DefaultModels, len(srv.cfg.MyList)))
+
+	if len(tracked) > 0 {
+		msg.WriteString("\n\n" + req.printer.T(i18n.TrackedListings) + "\n")
+		for idx, item := range tracked {
+			msg.WriteString(fmt.Sprintf("%d. (%s) %s - %s\n", idx+1, req.printer.YenString(item.Price), item.MatchedName, item.URL))
+		}
+	}
+
+	return srv.reply(req, msg.String())
+}
+
+func (srv *Service) handleMute(req *commandRequest) error {
+	if strings.EqualFold(req.args, "off") {
+		if err := srv.store.SetMutedUntil(req.sourceID, time.Time{}); err != nil {
+			return err
+		}
+		return srv.reply(req, req.printer.T(i18n.Unmuted))
+	}
+
+	d, err := command.ParseDuration(req.args)
+	if err != nil {
+		return srv.reply(req, req.printer.T(i18n.MuteUsage))
+	}
+
+	until := time.Now().Add(d)
+	if err := srv.store.SetMutedUntil(req.sourceID, until); err != nil {
+		return err
+	}
+	return srv.reply(req, req.printer.T(i18n.Muted, req.printer.Time(until.In(req.location))))
+}
+
+func (srv *Service) handleHelp(req *commandRequest) error {
+	return srv.reply(req, req.printer.T(i18n.Help))
+}
+
+func (srv *Service) handleHistory(req *commandRequest) error {
+	diff, err := srv.RunDiff()
+	if errors.Is(err, ErrNotEnoughRuns) {
+		return srv.reply(req, req.printer.T(i18n.NotEnoughRuns))
+	}
+	if err != nil {
+		return err
+	}
+	return srv.reply(req, line.GenerateDiffMessage(req.printer, diff))
+}
+
+func (srv *Service) handleStats(req *commandRequest) error {
+	if req.args == "" {
+		return srv.reply(req, req.printer.T(i18n.StatsUsage))
+	}
+
+	stats, err := srv.PriceStats(req.args)
+	if err != nil {
+		slog.WarnContext(req.ctx, "Error computing price stats", "query", req.args, "err", err)
+		return srv.reply(req, req.printer.T(i18n.UnknownModel, req.args))
+	}
+	return srv.reply(req, line.GenerateStatsMessage(req.printer, stats))
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/line/line-bot-sdk-go/v8/linebot/messaging_api"
+
+	"github.com/drifterz13/dino-noti/i18n"
+	"github.com/drifterz13/dino-noti/line"
+	"github.com/drifterz13/dino-noti/model"
+)
+
+// replyTokenTTL is kept below LINE's one minute reply token lifetime.
+const replyTokenTTL = 50 * time.Second
+
+// send replies with the event's reply token while it is still usable and
+// pushes to the event's source otherwise, e.g. after a long pipeline run.
+func (srv *Service) send(req *commandRequest, messages ...messaging_api.MessageInterface) error {
+	if req.replyToken != "" && !req.replied && time.Since(req.receivedAt) < replyTokenTTL {
+		req.replied = true
+
+		err := req.client.SendMessages(req.replyToken, messages...)
+		srv.recordDelivery(req.ctx, req.sourceID, model.ChannelLine, model.DeliveryReply, len(messages), err)
+		if err == nil {
+			return nil
+		}
+		slog.WarnContext(req.ctx, "Reply failed, falling back to push", "err", err)
+	}
+
+	if req.sourceID == "" {
+		return fmt.Errorf("cannot push without a source")
+	}
+
+	err := req.client.PushMessages(req.sourceID, messages...)
+	srv.recordDelivery(req.ctx, req.sourceID, model.ChannelLine, model.DeliveryPush, len(messages), err)
+	return err
+}
+
+func (srv *Service) reply(req *commandRequest, message string) error {
+	return srv.send(req, line.TextMessages(message)...)
+}
+
+// acknowledge uses up the reply token right away for commands that run the
+// pipeline, so their results are pushed once ready instead of replying with
+// an expired token.
+func (srv *Service) acknowledge(req *commandRequest) {
+	if err := srv.reply(req, req.printer.T(i18n.Searching)); err != nil {
+		slog.ErrorContext(req.ctx, "Error acknowledging", "err", err)
+	}
+
+	// LINE only supports the loading animation in one-on-one chats.
+	if req.sourceKind == model.SourceUser {
+		if err := req.client.ShowLoadingAnimation(req.sourceID); err != nil {
+			slog.WarnContext(req.ctx, "Error showing loading animation", "err", err)
+		}
+	}
+}
+
+func (srv *Service) recordDelivery(ctx context.Context, sourceID string, channel model.Channel, mode model.DeliveryMode, messages int, sendErr error) {
+	delivery := &model.Delivery{
+		SourceID:  sourceID,
+		Channel:   channel,
+		Mode:      mode,
+		Messages:  messages,
+		CreatedAt: time.Now(),
+	}
+	if sendErr != nil {
+		delivery.Error = sendErr.Error()
+	}
+
+	if err := srv.store.RecordDelivery(delivery); err != nil {
+		slog.ErrorContext(ctx, "Error recording delivery", "err", err)
+	}
+}
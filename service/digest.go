@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/drifterz13/dino-noti/i18n"
+	"github.com/drifterz13/dino-noti/model"
+)
+
+const digestCheckInterval = time.Minute
+
+// StartDigests periodically delivers the notifications held back by quiet
+// hours and digest mode, until the service shuts down.
+func (srv *Service) StartDigests() {
+	srv.background.Add(1)
+	go func() {
+		defer srv.background.Done()
+		ticker := time.NewTicker(digestCheckInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case now := <-ticker.C:
+				srv.flushDigests(srv.ctx, now)
+			case <-srv.stopping:
+				return
+			}
+		}
+	}()
+}
+
+// Location is the configured time zone, in which plain dates are read.
+func (srv *Service) Location() *time.Location {
+	return srv.location(model.Preferences{})
+}
+
+// location returns the subscriber's timezone, falling back to the configured
+// one.
+func (srv *Service) location(prefs model.Preferences) *time.Location {
+	for _, name := range []string{prefs.Timezone, srv.cfg.Timezone} {
+		if name == "" {
+			continue
+		}
+		if loc, err := time.LoadLocation(name); err == nil {
+			return loc
+		}
+	}
+	return time.Local
+}
+
+// printer renders text in the subscriber's language, falling back to the
+// configured one.
+func (srv *Service) printer(prefs model.Preferences) *i18n.Printer {
+	for _, code := range []string{prefs.Language, srv.cfg.DefaultLanguage} {
+		if lang, ok := i18n.ParseLang(code); ok {
+			return i18n.NewPrinter(lang)
+		}
+	}
+	return i18n.NewPrinter(i18n.English)
+}
+
+// holdNotification reports whether a notification should wait for the
+// subscriber's next digest instead of being sent now.
+func (srv *Service) holdNotification(sub model.Subscriber, now time.Time) bool {
+	if sub.Preferences.Digest != model.DigestImmediate {
+		return true
+	}
+	return sub.Preferences.QuietAt(now.In(srv.location(sub.Preferences)))
+}
+
+func (srv *Service) digestDue(sub model.Subscriber, now time.Time) bool {
+	if !sub.Active || sub.Muted(now) {
+		return false
+	}
+	if sub.Preferences.QuietAt(now.In(srv.location(sub.Preferences))) {
+		return false
+	}
+	return now.Sub(sub.LastDigestAt) >= sub.Preferences.Digest.Interval()
+}
+
+func (srv *Service) flushDigests(ctx context.Context, now time.Time) {
+	subscribers, err := srv.store.Subscribers()
+	if err != nil {
+		slog.ErrorContext(ctx, "Error loading subscribers", "err", err)
+		return
+	}
+
+	for _, sub := range subscribers {
+		if ctx.Err() != nil {
+			return
+		}
+		if !srv.digestDue(sub, now) {
+			continue
+		}
+
+		pending, err := srv.store.PendingNotifications(sub.ID)
+		if err != nil {
+			slog.ErrorContext(ctx, "Error loading pending notifications", "subscriber", sub.ID, "err", err)
+			continue
+		}
+		if len(pending) == 0 {
+			continue
+		}
+
+		// Undelivered digests stay pending and are retried on the next tick.
+		if !srv.dispatch(ctx, sub.ID, buildDigest(srv.printer(sub.Preferences), pending, srv.location(sub.Preferences))) {
+			continue
+		}
+
+		if err := srv.store.ClearPendingNotifications(sub.ID, pending[len(pending)-1].ID); err != nil {
+			slog.ErrorContext(ctx, "Error clearing pending notifications", "subscriber", sub.ID, "err", err)
+		}
+		if err := srv.store.SetLastDigestAt(sub.ID, now); err != nil {
+			slog.ErrorContext(ctx, "Error saving last digest", "subscriber", sub.ID, "err", err)
+		}
+	}
+}
+
+// buildDigest merges held notifications into one. A listing that appears in
+// several keeps its latest entry, labelled with the notification it came from.
+func buildDigest(p *i18n.Printer, pending []model.PendingNotification, loc *time.Location) model.Notification {
+	digest := model.Notification{
+		Language: string(p.Lang()),
+		Title:    p.T(i18n.DigestTitle, p.Time(pending[0].CreatedAt.In(loc))),
+		Details:  map[string]string{},
+	}
+
+	positions := map[string]int{}
+	for _, p := range pending {
+		label := strings.TrimSpace(p.Notification.Title)
+		for _, item := range p.Notification.Items {
+			if idx, ok := positions[item.AuctionID]; ok {
+				digest.Items[idx] = item
+			} else {
+				positions[item.AuctionID] = len(digest.Items)
+				digest.Items = append(digest.Items, item)
+			}
+
+			detail := label
+			if d := p.Notification.Detail(item); d != "" {
+				detail += ": " + d
+			}
+			digest.Details[item.AuctionID] = detail
+		}
+	}
+	return digest
+}
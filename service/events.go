@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/line/line-bot-sdk-go/v8/linebot/webhook"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/drifterz13/dino-noti/command"
+	"github.com/drifterz13/dino-noti/line"
+	"github.com/drifterz13/dino-noti/logging"
+	"github.com/drifterz13/dino-noti/metrics"
+	"github.com/drifterz13/dino-noti/model"
+	"github.com/drifterz13/dino-noti/tracing"
+)
+
+// handleEvent handles one webhook event. Its LINE API calls are bound to ctx,
+// which outlives the webhook request.
+func (srv *Service) handleEvent(ctx context.Context, event webhook.EventInterface, receivedAt time.Time) {
+	var prefs model.Preferences
+	source, ok := line.EventSource(event)
+	defer observeEvent(event, source, receivedAt)
+
+	ctx, span := tracing.Start(ctx, "webhook.event",
+		attribute.String("line.event.type", event.GetType()),
+		attribute.String("line.event.id", line.EventID(event)),
+		attribute.String("line.source.kind", string(source.Kind)),
+	)
+	defer span.End()
+	ctx = logging.WithEvent(ctx, line.EventID(event), source.ID, source.UserID)
+
+	lineBotClient, err := line.NewLineBotClient(ctx, srv.cfg)
+	if err != nil {
+		tracing.Fail(span, err)
+		slog.ErrorContext(ctx, "Error creating LINE Bot client", "err", err)
+		return
+	}
+
+	if ok {
+		if err := srv.store.UpsertSubscriber(source.ID, source.Kind); err != nil {
+			slog.ErrorContext(ctx, "Error saving subscriber", "err", err)
+		}
+		if sub, err := srv.store.Subscriber(source.ID); err == nil {
+			prefs = sub.Preferences
+		}
+	}
+
+	req := &commandRequest{
+		ctx:        ctx,
+		client:     lineBotClient,
+		receivedAt: receivedAt,
+		sourceID:   source.ID,
+		sourceKind: source.Kind,
+		userID:     source.UserID,
+		printer:    srv.printer(prefs),
+		location:   srv.location(prefs),
+	}
+
+	switch e := event.(type) {
+	case webhook.MessageEvent:
+		req.replyToken = e.ReplyToken
+		switch message := e.Message.(type) {
+		case webhook.TextMessageContent:
+			if text, ok := srv.commandText(req, message); ok {
+				srv.routeCommand(req, command.Parse(text))
+			}
+		case webhook.StickerMessageContent:
+			// Stickers are part of normal group conversation, so only a
+			// one-on-one chat runs a search on them.
+			if req.sourceKind == model.SourceUser {
+				err = srv.handleSearchCarousel(req)
+			}
+		default:
+			slog.DebugContext(ctx, "Ignoring unsupported message type", "type", fmt.Sprintf("%T", message))
+		}
+	case webhook.PostbackEvent:
+		req.replyToken = e.ReplyToken
+		srv.routePostback(req, e.Postback.Data)
+	case webhook.FollowEvent:
+		req.replyToken = e.ReplyToken
+		err = srv.handleSubscribe(req)
+	case webhook.JoinEvent:
+		req.replyToken = e.ReplyToken
+		err = srv.handleSubscribe(req)
+	case webhook.UnfollowEvent, webhook.LeaveEvent:
+		err = srv.handleUnsubscribe(req)
+	default:
+		slog.DebugContext(ctx, "Ignoring unsupported event type", "type", event.GetType())
+	}
+
+	if err != nil {
+		tracing.Fail(span, err)
+		slog.ErrorContext(ctx, "Error handling event", "type", event.GetType(), "err", err)
+	}
+}
+
+// observeEvent records how long an event took to handle since its webhook
+// request arrived.
+func observeEvent(event webhook.EventInterface, source line.Source, receivedAt time.Time) {
+	kind := string(source.Kind)
+	if kind == "" {
+		kind = "unknown"
+	}
+	metrics.WebhookDuration.WithLabelValues(event.GetType(), kind).Observe(time.Since(receivedAt).Seconds())
+}
+
+// commandText returns the command in a text message. In group chats the bot
+// only answers when it is mentioned or the message starts with the command
+// prefix, so the rest of the conversation is left alone.
+func (srv *Service) commandText(req *commandRequest, message webhook.TextMessageContent) (string, bool) {
+	text, mentioned := line.StripBotMention(message)
+	if req.sourceKind == model.SourceUser {
+		return text, true
+	}
+
+	text = strings.TrimSpace(text)
+	if withoutPrefix, ok := strings.CutPrefix(text, srv.cfg.CommandPrefix); ok {
+		return withoutPrefix, true
+	}
+	return text, mentioned
+}
+
+// handleSubscribe activates push notifications for a user who followed the
+// bot or a group it joined, then sends the onboarding messages.
+func (srv *Service) handleSubscribe(req *commandRequest) error {
+	if req.sourceID == "" {
+		return nil
+	}
+	if err := srv.store.SetActive(req.sourceID, true); err != nil {
+		return err
+	}
+
+	slog.InfoContext(req.ctx, "Subscribed", "kind", req.sourceKind)
+
+	return srv.send(req, line.BuildWelcomeMessages(req.printer, req.sourceKind, srv.cfg.CommandPrefix)...)
+}
+
+// handleUnsubscribe keeps the subscriber's data but stops pushing to it, since
+// LINE rejects pushes to users who unfollowed and chats the bot left.
+func (srv *Service) handleUnsubscribe(req *commandRequest) error {
+	if req.sourceID == "" {
+		return nil
+	}
+	if err := srv.store.SetActive(req.sourceID, false); err != nil {
+		return err
+	}
+
+	slog.InfoContext(req.ctx, "Unsubscribed", "kind", req.sourceKind)
+	return nil
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/drifterz13/dino-noti/export"
+	"github.com/drifterz13/dino-noti/i18n"
+	"github.com/drifterz13/dino-noti/model"
+)
+
+// FeedToken returns the secret token in the feed URLs of a chat, creating it
+// the first time. Rotating replaces it, which breaks the old URLs.
+func (srv *Service) FeedToken(sourceID string, rotate bool) (string, error) {
+	if _, err := srv.store.Subscriber(sourceID); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return "", fmt.Errorf("unknown chat %s: %w", sourceID, err)
+		}
+		return "", err
+	}
+
+	if !rotate {
+		token, err := srv.store.FeedToken(sourceID)
+		if !errors.Is(err, ErrNotFound) {
+			return token, err
+		}
+	}
+
+	secret := make([]byte, 20)
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("failed to generate feed token: %w", err)
+	}
+	token := hex.EncodeToString(secret)
+	if err := srv.store.SetFeedToken(sourceID, token); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// FeedURL is where the feed with the token is served in the format. It is
+// only a path when PUBLIC_URL is not configured.
+func (srv *Service) FeedURL(token string, format export.Format) string {
+	return fmt.Sprintf("%s/feeds/%s.%s", srv.cfg.PublicURL, token, format)
+}
+
+// FeedListings returns the listings on the watchlist of the chat a feed
+// token belongs to, along with that chat.
+func (srv *Service) FeedListings(token string, filter model.ListingFilter) (string, []model.Listing, error) {
+	sourceID, err := srv.store.FeedSource(token)
+	if err != nil {
+		return "", nil, fmt.Errorf("unknown feed: %w", err)
+	}
+	listings, err := srv.WatchlistListings(sourceID, filter)
+	return sourceID, listings, err
+}
+
+// WatchlistListings returns the listings within the filter of the models on
+// a chat's watchlist.
+func (srv *Service) WatchlistListings(sourceID string, filter model.ListingFilter) ([]model.Listing, error) {
+	watchlist, err := srv.store.Watchlist(sourceID)
+	if err != nil {
+		return nil, err
+	}
+	if len(watchlist) == 0 {
+		return nil, nil
+	}
+
+	filter.Models = nil
+	for _, entry := range watchlist {
+		filter.Models = append(filter.Models, entry.Model)
+	}
+	return srv.store.Listings(filter)
+}
+
+// handleFeed replies with the feed URLs of the chat, or new ones with
+// "feed reset".
+func (srv *Service) handleFeed(req *commandRequest) error {
+	if srv.cfg.PublicURL == "" {
+		return srv.reply(req, req.printer.T(i18n.FeedUnavailable))
+	}
+
+	rotate := strings.EqualFold(strings.TrimSpace(req.args), "reset")
+	token, err := srv.FeedToken(req.sourceID, rotate)
+	if err != nil {
+		return err
+	}
+
+	return srv.reply(req, req.printer.T(i18n.FeedLinks,
+		srv.FeedURL(token, export.Atom),
+		srv.FeedURL(token, export.RSS),
+		srv.FeedURL(token, export.CSV),
+		srv.FeedURL(token, export.JSONL),
+	))
+}
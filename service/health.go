@@ -0,0 +1,242 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/drifterz13/dino-noti/config"
+	"github.com/drifterz13/dino-noti/line"
+	"github.com/drifterz13/dino-noti/llm"
+	"github.com/drifterz13/dino-noti/model"
+	"github.com/drifterz13/dino-noti/parser"
+	"github.com/drifterz13/dino-noti/scraper"
+)
+
+const (
+	// Readiness probes are frequent and only touch the database.
+	readyCheckTimeout      = 2 * time.Second
+	diagnosticCheckTimeout = 10 * time.Second
+	// overdueIntervals is how many schedule intervals may pass without a run
+	// starting before the scheduled runs count as overdue.
+	overdueIntervals = 2
+)
+
+// check reports its status and a short description of what it found.
+type check struct {
+	name string
+	run  func(ctx context.Context) (model.CheckStatus, string)
+}
+
+// Ready checks that the service can do its work: the database answers, the
+// configuration is usable and scheduled runs are not overdue.
+func (srv *Service) Ready(ctx context.Context) []model.HealthCheck {
+	return runChecks(ctx, readyCheckTimeout, []check{
+		{"database", srv.checkDatabase},
+		{"config", srv.checkConfig},
+		{"schedule", srv.checkSchedule},
+	})
+}
+
+// Diagnostics checks the last scrape of each target, that the parser still
+// finds items on a live search page, and that Gemini and LINE accept the
+// configured credentials.
+func (srv *Service) Diagnostics(ctx context.Context) []model.HealthCheck {
+	checks := []check{
+		{"parser", srv.checkParser},
+		{"llm", srv.checkLLM},
+		{"line", srv.checkLine},
+	}
+
+	targets, err := srv.ScrapeTargets()
+	if err != nil {
+		checks = append(checks, check{"targets", failed(err)})
+	}
+	for _, target := range targets {
+		checks = append(checks, check{"target " + target.Name, func(ctx context.Context) (model.CheckStatus, string) {
+			return srv.checkTarget(ctx, target)
+		}})
+	}
+	return runChecks(ctx, diagnosticCheckTimeout, checks)
+}
+
+// runChecks runs the checks concurrently. A check still running after the
+// timeout fails, even when it does not stop on ctx.
+func runChecks(ctx context.Context, timeout time.Duration, checks []check) []model.HealthCheck {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	type finished struct {
+		index  int
+		result model.HealthCheck
+	}
+	done := make(chan finished, len(checks))
+
+	results := make([]model.HealthCheck, len(checks))
+	for i, c := range checks {
+		results[i] = model.HealthCheck{Name: c.name, Status: model.CheckFail, Detail: fmt.Sprintf("timed out after %s", timeout), Duration: timeout}
+		go func() {
+			start := time.Now()
+			status, detail := c.run(ctx)
+			done <- finished{i, model.HealthCheck{Name: c.name, Status: status, Detail: detail, Duration: time.Since(start)}}
+		}()
+	}
+
+	for range checks {
+		select {
+		case f := <-done:
+			results[f.index] = f.result
+		case <-ctx.Done():
+			return results
+		}
+	}
+	return results
+}
+
+func failed(err error) func(context.Context) (model.CheckStatus, string) {
+	return func(context.Context) (model.CheckStatus, string) {
+		return model.CheckFail, err.Error()
+	}
+}
+
+func (srv *Service) checkDatabase(ctx context.Context) (model.CheckStatus, string) {
+	if err := srv.store.Ping(ctx); err != nil {
+		return model.CheckFail, err.Error()
+	}
+	return model.CheckOK, "reachable"
+}
+
+func (srv *Service) checkConfig(ctx context.Context) (model.CheckStatus, string) {
+	for _, requirement := range []config.Requirement{config.RequireGemini, config.RequireLine} {
+		if err := srv.cfg.Check(requirement); err != nil {
+			return model.CheckFail, err.Error()
+		}
+	}
+
+	targets, err := srv.ScrapeTargets()
+	if err != nil {
+		return model.CheckFail, err.Error()
+	}
+	if len(targets) == 0 {
+		return model.CheckWarn, "no enabled targets"
+	}
+	return model.CheckOK, fmt.Sprintf("%d enabled targets", len(targets))
+}
+
+func (srv *Service) checkSchedule(ctx context.Context) (model.CheckStatus, string) {
+	interval := srv.cfg.ScheduleInterval
+	if interval <= 0 {
+		return model.CheckOK, "scheduled runs are disabled"
+	}
+
+	lastStart, err := srv.store.LastRunStart(ctx)
+	if err != nil {
+		return model.CheckFail, err.Error()
+	}
+	since := srv.startedAt
+	if lastStart.After(since) {
+		since = lastStart
+	}
+
+	elapsed := time.Since(since).Round(time.Second)
+	if elapsed > overdueIntervals*interval {
+		return model.CheckFail, fmt.Sprintf("no run started for %s, expected one every %s", elapsed, interval)
+	}
+	if lastStart.IsZero() {
+		return model.CheckOK, fmt.Sprintf("no run yet, expected one every %s", interval)
+	}
+	return model.CheckOK, fmt.Sprintf("last run started %s ago", elapsed)
+}
+
+func (srv *Service) checkTarget(ctx context.Context, target model.Target) (model.CheckStatus, string) {
+	scrapes, err := srv.store.TargetScrapes(ctx)
+	if err != nil {
+		return model.CheckFail, err.Error()
+	}
+
+	scrape, ok := scrapes[target.URL]
+	if !ok {
+		return model.CheckWarn, "never scraped successfully"
+	}
+
+	detail := fmt.Sprintf("last scraped %s ago: %d pages, %d items",
+		time.Since(scrape.ScrapedAt).Round(time.Second), scrape.Pages, scrape.Items)
+	switch {
+	case scrape.Items == 0:
+		return model.CheckWarn, detail
+	case srv.cfg.ScheduleInterval > 0 && time.Since(scrape.ScrapedAt) > overdueIntervals*srv.cfg.ScheduleInterval:
+		return model.CheckWarn, detail
+	}
+	return model.CheckOK, detail
+}
+
+// checkParser fetches the first page of the first target and counts what each
+// parser selector finds on it.
+func (srv *Service) checkParser(ctx context.Context) (model.CheckStatus, string) {
+	targets, err := srv.ScrapeTargets()
+	if err != nil {
+		return model.CheckFail, err.Error()
+	}
+	if len(targets) == 0 {
+		return model.CheckWarn, "no enabled targets to fetch"
+	}
+
+	pageURL, err := pageURL(targets[0].URL, 1)
+	if err != nil {
+		return model.CheckFail, err.Error()
+	}
+	htmlContent, err := scraper.FetchPage(ctx, pageURL)
+	if err != nil {
+		return model.CheckFail, err.Error()
+	}
+	counts, err := parser.CheckSelectors(htmlContent)
+	if err != nil {
+		return model.CheckFail, err.Error()
+	}
+
+	var parts []string
+	for _, count := range counts {
+		parts = append(parts, fmt.Sprintf("%s: %d", count.Selector, count.Matches))
+	}
+	detail := strings.Join(parts, ", ")
+
+	cards := counts[0].Matches
+	if cards == 0 {
+		return model.CheckFail, detail
+	}
+	for _, count := range counts[1:] {
+		if count.Matches < cards {
+			return model.CheckWarn, detail
+		}
+	}
+	return model.CheckOK, detail
+}
+
+func (srv *Service) checkLLM(ctx context.Context) (model.CheckStatus, string) {
+	if srv.cfg.GeminiAPIKey == "" {
+		return model.CheckFail, "GEMINI_API_KEY is not set"
+	}
+
+	llmClient, err := llm.NewLLMClient(ctx, srv.cfg.GeminiAPIKey)
+	if err == nil {
+		err = llmClient.Ping(ctx)
+	}
+	if err != nil {
+		return model.CheckFail, err.Error()
+	}
+	return model.CheckOK, llmClient.Model() + " is reachable"
+}
+
+func (srv *Service) checkLine(ctx context.Context) (model.CheckStatus, string) {
+	lineBotClient, err := line.NewLineBotClient(ctx, srv.cfg)
+	if err != nil {
+		return model.CheckFail, err.Error()
+	}
+
+	name, err := lineBotClient.BotName()
+	if err != nil {
+		return model.CheckFail, err.Error()
+	}
+	return model.CheckOK, fmt.Sprintf("token is valid for %s", name)
+}
@@ -0,0 +1,273 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/drifterz13/dino-noti/i18n"
+	"github.com/drifterz13/dino-noti/line"
+	"github.com/drifterz13/dino-noti/logging"
+	"github.com/drifterz13/dino-noti/model"
+	"github.com/drifterz13/dino-noti/tracing"
+)
+
+const (
+	// jobPollInterval bounds how long a job due for a retry waits for a worker.
+	jobPollInterval = 5 * time.Second
+	jobRetryBackoff = 30 * time.Second
+)
+
+// EnqueueRun queues a pipeline run, or joins the one in the queue when it has
+// not matched yet. The requester, if any, is pushed the results once ready.
+func (srv *Service) EnqueueRun(ctx context.Context, trigger model.RunTrigger, requester *model.JobRequester) (int64, error) {
+	id, joined, err := srv.store.EnqueueJob(trigger, requester, tracing.Parent(ctx))
+	if err != nil {
+		return 0, err
+	}
+
+	if joined {
+		slog.InfoContext(ctx, "Joining queued job", "job_id", id)
+	} else {
+		slog.InfoContext(ctx, "Queued job", "job_id", id, "trigger", trigger)
+	}
+
+	select {
+	case srv.jobWake <- struct{}{}:
+	default:
+	}
+	return id, nil
+}
+
+// StartWorkers requeues the jobs a previous process left running and starts
+// the configured number of workers, which stop when the service shuts down.
+func (srv *Service) StartWorkers() {
+	requeued, err := srv.store.RequeueRunningJobs()
+	if err != nil {
+		slog.Error("Error requeueing interrupted jobs", "err", err)
+	} else if requeued > 0 {
+		slog.Info("Requeued interrupted jobs", "jobs", requeued)
+	}
+
+	for i := 0; i < srv.cfg.JobWorkers; i++ {
+		srv.background.Add(1)
+		go srv.work()
+	}
+}
+
+func (srv *Service) work() {
+	defer srv.background.Done()
+
+	ticker := time.NewTicker(jobPollInterval)
+	defer ticker.Stop()
+
+	for {
+		for srv.processNextJob() {
+		}
+
+		select {
+		case <-srv.jobWake:
+		case <-ticker.C:
+		case <-srv.stopping:
+			return
+		}
+	}
+}
+
+// RunJob queues a run and processes it in this process instead of on a
+// worker. It returns the job as it ended up, which is queued for a retry
+// when a stage failed.
+func (srv *Service) RunJob(ctx context.Context, trigger model.RunTrigger) (*model.Job, error) {
+	id, err := srv.EnqueueRun(ctx, trigger, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := srv.store.ClaimQueuedJob(id, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if job == nil {
+		return nil, fmt.Errorf("job %d is already running in another process", id)
+	}
+
+	srv.processJob(ctx, job)
+	return srv.store.Job(id)
+}
+
+// processNextJob runs the next due job and reports whether there was one.
+func (srv *Service) processNextJob() bool {
+	select {
+	case <-srv.stopping:
+		return false
+	default:
+	}
+
+	job, err := srv.store.ClaimJob(time.Now())
+	if err != nil {
+		slog.Error("Error claiming job", "err", err)
+		return false
+	}
+	if job == nil {
+		return false
+	}
+
+	srv.processJob(srv.ctx, job)
+	return true
+}
+
+// processJob runs a job from its current stage, checkpointing after each one.
+func (srv *Service) processJob(ctx context.Context, job *model.Job) {
+	ctx, span := tracing.Start(tracing.WithParent(ctx, job.TraceParent), "pipeline.run",
+		attribute.Int64("job.id", job.ID),
+		attribute.String("run.trigger", string(job.Trigger)),
+	)
+	defer span.End()
+
+	ctx = logging.WithJob(ctx, job.ID)
+	if job.RunID == 0 {
+		run := &model.Run{Trigger: job.Trigger, StartedAt: time.Now()}
+		if err := srv.store.CreateRun(run); err != nil {
+			srv.failJob(ctx, job, err)
+			return
+		}
+		job.RunID = run.ID
+		if err := srv.store.SaveJobProgress(job); err != nil {
+			srv.failJob(ctx, job, err)
+			return
+		}
+	}
+	ctx = logging.WithRun(ctx, job.RunID)
+	span.SetAttributes(attribute.Int64("run.id", job.RunID))
+	slog.InfoContext(ctx, "Processing job", "trigger", job.Trigger, "stage", job.Stage)
+
+	for job.Stage != model.StageDone {
+		stage, ok := pipelineStages[job.Stage]
+		if !ok {
+			srv.failJob(ctx, job, fmt.Errorf("unknown stage %q", job.Stage))
+			return
+		}
+		stageCtx, stageSpan := tracing.Start(ctx, "pipeline."+string(job.Stage))
+		err := stage.run(srv, stageCtx, job)
+		tracing.End(stageSpan, err)
+		if err != nil {
+			srv.failJob(ctx, job, fmt.Errorf("%s stage: %w", job.Stage, err))
+			return
+		}
+
+		// Retries are counted per stage.
+		job.Stage = stage.next
+		job.Attempts = 0
+		if err := srv.store.SaveJobProgress(job); err != nil {
+			srv.failJob(ctx, job, err)
+			return
+		}
+	}
+
+	if err := srv.store.FinishJob(job.ID); err != nil {
+		slog.ErrorContext(ctx, "Error finishing job", "err", err)
+	}
+}
+
+// failJob requeues a failed job with a backoff, or moves it to the dead
+// letters once it is out of attempts. A job interrupted by shutdown is
+// requeued as is, to resume on the next start.
+func (srv *Service) failJob(ctx context.Context, job *model.Job, jobErr error) {
+	job.LastError = jobErr.Error()
+	tracing.Fail(trace.SpanFromContext(ctx), jobErr)
+
+	if ctx.Err() != nil {
+		slog.WarnContext(ctx, "Job interrupted, requeueing", "stage", job.Stage)
+		if err := srv.store.RetryJob(job, time.Now()); err != nil {
+			slog.ErrorContext(ctx, "Error requeueing job", "err", err)
+		}
+		return
+	}
+
+	job.Attempts++
+	if job.Attempts < srv.cfg.JobMaxAttempts {
+		runAfter := time.Now().Add(time.Duration(job.Attempts) * jobRetryBackoff)
+		slog.WarnContext(ctx, "Job failed, retrying", "attempt", job.Attempts, "retry_at", runAfter, "err", jobErr)
+		if err := srv.store.RetryJob(job, runAfter); err != nil {
+			slog.ErrorContext(ctx, "Error requeueing job", "err", err)
+		}
+		return
+	}
+
+	slog.ErrorContext(ctx, "Job failed, giving up", "attempts", job.Attempts, "err", jobErr)
+	if err := srv.store.KillJob(job); err != nil {
+		slog.ErrorContext(ctx, "Error moving job to dead letters", "err", err)
+	}
+	if job.RunID != 0 {
+		if _, err := srv.finishRun(ctx, job, jobErr); err != nil {
+			slog.ErrorContext(ctx, "Error finishing run", "err", err)
+		}
+	}
+	srv.forEachRequester(ctx, job, func(req *commandRequest, _ model.JobRequester) error {
+		return srv.reply(req, req.printer.T(i18n.SearchFailed))
+	})
+}
+
+// replyRequesters pushes the results of a job to every chat waiting for it.
+func (srv *Service) replyRequesters(ctx context.Context, job *model.Job) {
+	srv.forEachRequester(ctx, job, func(req *commandRequest, requester model.JobRequester) error {
+		return srv.replyItems(req, slices.Clone(job.Checkpoint.Matched), requester.AsCarousel, len(job.Checkpoint.Anomalies) > 0)
+	})
+}
+
+func (srv *Service) forEachRequester(ctx context.Context, job *model.Job, send func(req *commandRequest, requester model.JobRequester) error) {
+	requesters, err := srv.store.JobRequesters(job.ID)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error loading requesters", "err", err)
+		return
+	}
+	if len(requesters) == 0 {
+		return
+	}
+	// Chats only get replies from real runs.
+	if srv.redirect != nil {
+		slog.InfoContext(ctx, "Not replying to waiting chats", "chats", len(requesters))
+		return
+	}
+
+	lineBotClient, err := line.NewLineBotClient(ctx, srv.cfg)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error creating LINE Bot client", "err", err)
+		return
+	}
+
+	for _, requester := range requesters {
+		if err := send(srv.pushRequest(ctx, lineBotClient, requester.SourceID), requester); err != nil {
+			slog.ErrorContext(ctx, "Error sending results", "subscriber", requester.SourceID, "err", err)
+		}
+	}
+}
+
+// DeadJobs returns up to limit jobs that ran out of attempts, newest first.
+func (srv *Service) DeadJobs(limit int) ([]model.DeadJob, error) {
+	return srv.store.DeadJobs(limit)
+}
+
+// pushRequest is a request without a reply token, for sending to a chat
+// outside of the event that started the work.
+func (srv *Service) pushRequest(ctx context.Context, lineBotClient *line.LineBotClient, sourceID string) *commandRequest {
+	req := &commandRequest{
+		ctx:      logging.With(ctx, slog.String("source_id", sourceID)),
+		client:   lineBotClient,
+		sourceID: sourceID,
+	}
+
+	var prefs model.Preferences
+	if sub, err := srv.store.Subscriber(sourceID); err == nil {
+		prefs = sub.Preferences
+		req.sourceKind = sub.Kind
+	}
+	req.printer = srv.printer(prefs)
+	req.location = srv.location(prefs)
+	return req
+}
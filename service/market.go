@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"time"
+
+	"github.com/drifterz13/dino-noti/i18n"
+	"github.com/drifterz13/dino-noti/market"
+	"github.com/drifterz13/dino-noti/model"
+)
+
+const marketWindowDays = 90
+
+// annotateMarketPrices compares every item against the recorded prices of its
+// canonical model, leaving MarketPrice zero when history is too thin.
+func (srv *Service) annotateMarketPrices(ctx context.Context, items []model.MatchedItem) {
+	since := time.Now().AddDate(0, 0, -marketWindowDays)
+	historyByModel := make(map[string][]model.ListingPrice)
+
+	for i := range items {
+		item := &items[i]
+
+		history, ok := historyByModel[item.MatchedName]
+		if !ok {
+			var err error
+			history, err = srv.store.ModelPrices(item.MatchedName, since)
+			if err != nil {
+				slog.ErrorContext(ctx, "Error loading price history", "model", item.MatchedName, "err", err)
+			}
+			historyByModel[item.MatchedName] = history
+		}
+
+		price, err := model.ParsePrice(item.Price)
+		if err != nil {
+			continue
+		}
+
+		// Exclude earlier observations of the same listing from its own reference.
+		var others []model.ListingPrice
+		for _, observation := range history {
+			if observation.AuctionID != item.AuctionID {
+				others = append(others, observation)
+			}
+		}
+
+		estimate, ok := market.EstimatePrice(item.OriginalName, price, others)
+		if !ok {
+			continue
+		}
+		item.MarketPrice = estimate.ReferencePrice
+		item.MarketDiffPercent = estimate.DiffPercent
+		item.MarketPercentile = estimate.Percentile
+	}
+}
+
+// newDeals returns listings seen for the first time whose price falls below
+// the configured percentile of their model's history.
+func (srv *Service) newDeals(items []model.MatchedItem) []model.MatchedItem {
+	var deals []model.MatchedItem
+	for _, item := range items {
+		if item.IsNew && item.MarketPrice > 0 && item.MarketPercentile < srv.cfg.DealPercentile {
+			deals = append(deals, item)
+		}
+	}
+	market.SortByDeal(deals)
+
+	return deals
+}
+
+// markNewListings flags the items whose listing was never recorded before.
+func (srv *Service) markNewListings(items []model.MatchedItem) error {
+	var auctionIDs []string
+	for _, item := range items {
+		auctionIDs = append(auctionIDs, item.AuctionID)
+	}
+
+	existing, err := srv.store.ExistingListings(auctionIDs)
+	if err != nil {
+		return err
+	}
+	for i := range items {
+		items[i].IsNew = items[i].AuctionID != "" && !existing[items[i].AuctionID]
+	}
+	return nil
+}
+
+// markPriceDrops records the earlier price on items that got cheaper.
+func markPriceDrops(items []model.MatchedItem, drops []model.PriceDrop) {
+	oldPrices := make(map[string]int, len(drops))
+	for _, drop := range drops {
+		oldPrices[drop.Item.AuctionID] = drop.OldPrice
+	}
+	for i := range items {
+		items[i].PreviousPrice = oldPrices[items[i].AuctionID]
+	}
+}
+
+// annotateCosts estimates the price in baht, and the landed cost including
+// fees and shipping, when an exchange rate is configured.
+func (srv *Service) annotateCosts(items []model.MatchedItem) {
+	if srv.cfg.THBRate <= 0 {
+		return
+	}
+	for i := range items {
+		price, err := model.ParsePrice(items[i].Price)
+		if err != nil {
+			continue
+		}
+		items[i].PriceTHB = int(math.Round(float64(price) * srv.cfg.THBRate))
+		items[i].LandedTHB = int(math.Round(float64(price+srv.cfg.LandedExtraYen) * srv.cfg.THBRate))
+	}
+}
+
+func (srv *Service) notifyDeals(ctx context.Context, deals []model.MatchedItem) {
+	srv.pushToSubscribers(ctx, func(sub model.Subscriber, filter model.ItemFilter) *model.Notification {
+		var visible []model.MatchedItem
+		for _, item := range filter.Apply(deals) {
+			if sub.Preferences.AllowsDeal(item) {
+				visible = append(visible, item)
+			}
+		}
+		if len(visible) == 0 {
+			return nil
+		}
+
+		p := srv.printer(sub.Preferences)
+		details := make(map[string]string, len(visible))
+		for _, item := range visible {
+			details[item.AuctionID] = p.T(i18n.DealDetail, p.Yen(item.MarketPrice), p.Percent(item.MarketDiffPercent))
+		}
+		return &model.Notification{Language: string(p.Lang()), Title: p.T(i18n.DealsTitle), Items: visible, Details: details}
+	})
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/drifterz13/dino-noti/model"
+	"github.com/drifterz13/dino-noti/notify"
+)
+
+func newNotifiers(srv *Service) map[model.Channel]notify.Notifier {
+	return map[model.Channel]notify.Notifier{
+		model.ChannelLine:    notify.NewLineNotifier(srv.cfg),
+		model.ChannelDiscord: notify.NewDiscordNotifier(nil),
+		model.ChannelSlack:   notify.NewSlackNotifier(nil),
+		model.ChannelEmail:   notify.NewEmailNotifier(srv.cfg),
+		model.ChannelWebhook: notify.NewWebhookNotifier(nil),
+	}
+}
+
+// pushToSubscribers sends every active subscriber that is not muted the
+// notification rendered for them, on each of their channels. The render
+// function receives the subscriber's item filter and returns nil when there
+// is nothing to send. Once ctx is done, notifications are queued for the next
+// digest instead of being dropped.
+func (srv *Service) pushToSubscribers(ctx context.Context, render func(sub model.Subscriber, filter model.ItemFilter) *model.Notification) {
+	subscribers, err := srv.store.Subscribers()
+	if err != nil {
+		slog.ErrorContext(ctx, "Error loading subscribers", "err", err)
+		return
+	}
+
+	now := time.Now()
+	for _, sub := range subscribers {
+		if !sub.Active || sub.Muted(now) {
+			continue
+		}
+
+		filter, err := srv.store.ItemFilter(sub.ID, now)
+		if err != nil {
+			slog.ErrorContext(ctx, "Error loading item filter", "subscriber", sub.ID, "err", err)
+			continue
+		}
+
+		notification := render(sub, filter)
+		if notification == nil {
+			continue
+		}
+
+		if srv.redirect == nil && (srv.holdNotification(sub, now) || ctx.Err() != nil) {
+			if err := srv.store.QueueNotification(sub.ID, *notification); err != nil {
+				slog.ErrorContext(ctx, "Error queueing notification", "subscriber", sub.ID, "err", err)
+			}
+			continue
+		}
+		srv.dispatch(ctx, sub.ID, *notification)
+	}
+}
+
+// RedirectNotifications sends every notification to n instead of the
+// subscribers' channels, regardless of quiet hours and digest settings. It is
+// meant for dry runs and must be called before any work starts.
+func (srv *Service) RedirectNotifications(n notify.Notifier) {
+	srv.redirect = n
+}
+
+// dispatch sends a notification on each of the subscriber's channels and
+// reports whether it was delivered on any of them.
+func (srv *Service) dispatch(ctx context.Context, subscriberID string, n model.Notification) bool {
+	if srv.redirect != nil {
+		if err := srv.redirect.Notify(ctx, subscriberID, n); err != nil {
+			slog.ErrorContext(ctx, "Error notifying", "subscriber", subscriberID, "err", err)
+			return false
+		}
+		return true
+	}
+
+	routes, err := srv.routes(subscriberID)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error loading channels", "subscriber", subscriberID, "err", err)
+		return false
+	}
+	delivered := false
+	for _, route := range routes {
+		err := srv.notifiers[route.Channel].Notify(ctx, route.Target, n)
+		srv.recordDelivery(ctx, subscriberID, route.Channel, model.DeliveryPush, 1, err)
+		if err != nil {
+			slog.ErrorContext(ctx, "Error notifying", "subscriber", subscriberID, "channel", route.Channel, "err", err)
+			continue
+		}
+		delivered = true
+	}
+	return delivered
+}
+
+// routes returns the channels a subscriber is notified on, which is their
+// own LINE chat until they configure any.
+func (srv *Service) routes(subscriberID string) ([]model.Route, error) {
+	routes, err := srv.store.Routes(subscriberID)
+	if err != nil {
+		return nil, err
+	}
+	if len(routes) == 0 {
+		routes = []model.Route{{Channel: model.ChannelLine, Target: subscriberID}}
+	}
+	return routes, nil
+}
+
+// visibleItems drops the listings and models the source chose to ignore.
+func (srv *Service) visibleItems(ctx context.Context, sourceID string, items []model.MatchedItem) []model.MatchedItem {
+	filter, err := srv.store.ItemFilter(sourceID, time.Now())
+	if err != nil {
+		slog.ErrorContext(ctx, "Error loading item filter", "subscriber", sourceID, "err", err)
+		return items
+	}
+	return filter.Apply(items)
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/drifterz13/dino-noti/command"
+	"github.com/drifterz13/dino-noti/i18n"
+	"github.com/drifterz13/dino-noti/line"
+	"github.com/drifterz13/dino-noti/store"
+)
+
+type postbackHandler func(srv *Service, req *commandRequest, params url.Values) error
+
+var postbackHandlers = map[string]postbackHandler{
+	line.PostbackCommand: (*Service).handleCommandPostback,
+	line.PostbackTrack:   (*Service).handleTrackPostback,
+	line.PostbackIgnore:  (*Service).handleIgnorePostback,
+	line.PostbackSnooze:  (*Service).handleSnoozePostback,
+	line.PostbackMore:    (*Service).handleMorePostback,
+}
+
+const modelSnoozeDuration = 7 * 24 * time.Hour
+
+func (srv *Service) routePostback(req *commandRequest, data string) {
+	action, params, err := line.ParsePostback(data)
+	if err != nil {
+		slog.WarnContext(req.ctx, "Error parsing postback", "err", err)
+		return
+	}
+
+	handler, ok := postbackHandlers[action]
+	if !ok {
+		slog.WarnContext(req.ctx, "Ignoring postback with unknown action", "action", action)
+		return
+	}
+
+	if err := handler(srv, req, params); err != nil {
+		slog.ErrorContext(req.ctx, "Error handling postback", "action", action, "err", err)
+	}
+}
+
+// handleCommandPostback runs a command from a button, e.g. the onboarding
+// quick replies.
+func (srv *Service) handleCommandPostback(req *commandRequest, params url.Values) error {
+	srv.routeCommand(req, command.Parse(params.Get("name")+" "+params.Get("args")))
+	return nil
+}
+
+func (srv *Service) handleTrackPostback(req *commandRequest, params url.Values) error {
+	auctionID := params.Get("id")
+	if auctionID == "" {
+		return fmt.Errorf("missing listing id")
+	}
+	if err := srv.store.TrackListing(req.sourceID, auctionID); err != nil {
+		return err
+	}
+	return srv.reply(req, req.printer.T(i18n.Tracking))
+}
+
+func (srv *Service) handleIgnorePostback(req *commandRequest, params url.Values) error {
+	auctionID := params.Get("id")
+	if auctionID == "" {
+		return fmt.Errorf("missing listing id")
+	}
+	if err := srv.store.IgnoreListing(req.sourceID, auctionID); err != nil {
+		return err
+	}
+	return srv.reply(req, req.printer.T(i18n.IgnoredListing))
+}
+
+func (srv *Service) handleSnoozePostback(req *commandRequest, params url.Values) error {
+	matchedName := params.Get("model")
+	if matchedName == "" {
+		return fmt.Errorf("missing model")
+	}
+	until := time.Now().Add(modelSnoozeDuration)
+	if err := srv.store.IgnoreModel(req.sourceID, matchedName, until); err != nil {
+		return err
+	}
+	return srv.reply(req, req.printer.T(i18n.IgnoringModel, matchedName, req.printer.Date(until.In(req.location))))
+}
+
+// handleMorePostback sends the next page of a stored result set.
+func (srv *Service) handleMorePostback(req *commandRequest, params url.Values) error {
+	id, err := strconv.ParseInt(params.Get("set"), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid result set %q: %w", params.Get("set"), err)
+	}
+	offset, err := strconv.Atoi(params.Get("offset"))
+	if err != nil || offset < 0 {
+		return fmt.Errorf("invalid offset %q", params.Get("offset"))
+	}
+
+	items, err := srv.store.ResultSet(id, req.sourceID)
+	if errors.Is(err, store.ErrNotFound) || (err == nil && offset >= len(items)) {
+		return srv.reply(req, req.printer.T(i18n.ResultsExpired))
+	}
+	if err != nil {
+		return err
+	}
+
+	return srv.send(req, line.ItemPageMessages(req.printer, line.ItemPage{
+		ResultSetID: id,
+		Items:       items,
+		Offset:      offset,
+		AsCarousel:  params.Get("view") == "carousel",
+
+		PlaceholderImageURL: srv.cfg.PlaceholderImageURL,
+	})...)
+}
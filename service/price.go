@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/drifterz13/dino-noti/i18n"
+	"github.com/drifterz13/dino-noti/market"
+	"github.com/drifterz13/dino-noti/matcher"
+	"github.com/drifterz13/dino-noti/model"
+)
+
+var statsWindows = []int{30, 90}
+
+// PriceStats summarizes the prices observed for a canonical model over the
+// stats windows. The model name is resolved fuzzily against known models.
+func (srv *Service) PriceStats(query string) ([]model.PriceStats, error) {
+	name, err := srv.resolveModel(query)
+	if err != nil {
+		return nil, err
+	}
+
+	var stats []model.PriceStats
+	for _, days := range statsWindows {
+		prices, err := srv.store.ModelPrices(name, time.Now().AddDate(0, 0, -days))
+		if err != nil {
+			return nil, err
+		}
+		s := summarizePrices(prices)
+		s.Model = name
+		s.Days = days
+		stats = append(stats, s)
+	}
+
+	return stats, nil
+}
+
+func (srv *Service) resolveModel(query string) (string, error) {
+	knownModels, err := srv.store.KnownModels()
+	if err != nil {
+		return "", err
+	}
+	candidates := append(srv.searchTerms(), knownModels...)
+
+	for _, candidate := range candidates {
+		if strings.EqualFold(candidate, query) {
+			return candidate, nil
+		}
+	}
+
+	matched, name := matcher.MatchItem(query, candidates)
+	if !matched {
+		return "", fmt.Errorf("unknown model %q: %w", query, ErrNotFound)
+	}
+	return name, nil
+}
+
+// Models returns the search terms and every model with recorded listings,
+// sorted and without duplicates.
+func (srv *Service) Models() ([]string, error) {
+	knownModels, err := srv.store.KnownModels()
+	if err != nil {
+		return nil, err
+	}
+
+	var models []string
+	for _, name := range append(srv.searchTerms(), knownModels...) {
+		if !slices.ContainsFunc(models, func(m string) bool { return strings.EqualFold(m, name) }) {
+			models = append(models, name)
+		}
+	}
+	slices.SortFunc(models, func(a, b string) int { return strings.Compare(strings.ToLower(a), strings.ToLower(b)) })
+	return models, nil
+}
+
+// Listings returns the matched listings within the filter.
+func (srv *Service) Listings(filter model.ListingFilter) ([]model.Listing, error) {
+	return srv.store.Listings(filter)
+}
+
+// ListingPrices returns a listing with every price observed for it.
+func (srv *Service) ListingPrices(auctionID string) (*model.Listing, []model.PricePoint, error) {
+	listing, err := srv.store.Listing(auctionID)
+	if err != nil {
+		return nil, nil, err
+	}
+	prices, err := srv.store.PriceHistory(auctionID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return listing, prices, nil
+}
+
+// ModelPriceHistory returns the prices observed for a canonical model since
+// the given time, along with the model name the query resolved to.
+func (srv *Service) ModelPriceHistory(query string, since time.Time) (string, []model.PricePoint, error) {
+	name, err := srv.resolveModel(query)
+	if err != nil {
+		return "", nil, err
+	}
+	prices, err := srv.store.ModelPriceHistory(name, since)
+	if err != nil {
+		return "", nil, err
+	}
+	return name, prices, nil
+}
+
+func summarizePrices(listingPrices []model.ListingPrice) model.PriceStats {
+	if len(listingPrices) == 0 {
+		return model.PriceStats{}
+	}
+
+	sorted := make([]int, len(listingPrices))
+	for i, p := range listingPrices {
+		sorted[i] = p.Price
+	}
+	sort.Ints(sorted)
+
+	return model.PriceStats{
+		Count:  len(sorted),
+		Min:    sorted[0],
+		Median: market.Median(sorted),
+		Max:    sorted[len(sorted)-1],
+	}
+}
+
+// notifyPriceDrops pushes each drop to the subscribers tracking the listing,
+// or the earlier listing in case of a cheaper relist.
+func (srv *Service) notifyPriceDrops(ctx context.Context, drops []model.PriceDrop) {
+	var auctionIDs []string
+	for _, drop := range drops {
+		auctionIDs = append(auctionIDs, drop.Item.AuctionID)
+		if drop.RelistOf != "" {
+			auctionIDs = append(auctionIDs, drop.RelistOf)
+		}
+	}
+
+	trackers, err := srv.store.Trackers(auctionIDs)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error loading trackers", "err", err)
+		return
+	}
+
+	srv.pushToSubscribers(ctx, func(sub model.Subscriber, filter model.ItemFilter) *model.Notification {
+		p := srv.printer(sub.Preferences)
+		var tracked []model.MatchedItem
+		details := map[string]string{}
+		for _, drop := range drops {
+			if !filter.Allows(drop.Item) {
+				continue
+			}
+			if !slices.Contains(trackers[drop.Item.AuctionID], sub.ID) && !slices.Contains(trackers[drop.RelistOf], sub.ID) {
+				continue
+			}
+
+			label := p.T(i18n.PriceDropLabel)
+			if drop.RelistOf != "" {
+				label = p.T(i18n.RelistLabel)
+			}
+			tracked = append(tracked, drop.Item)
+			details[drop.Item.AuctionID] = p.T(i18n.PriceDropDetail, p.Yen(drop.OldPrice), p.Yen(drop.NewPrice), label)
+		}
+		if len(tracked) == 0 {
+			return nil
+		}
+		return &model.Notification{Language: string(p.Lang()), Title: p.T(i18n.PriceDropsTitle), Items: tracked, Details: details}
+	})
+}
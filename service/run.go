@@ -0,0 +1,266 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/drifterz13/dino-noti/metrics"
+	"github.com/drifterz13/dino-noti/model"
+	"github.com/drifterz13/dino-noti/scraper"
+)
+
+var ErrNotEnoughRuns = errors.New("not enough runs to compare")
+
+// pipelineStages run a job one stage at a time. Each stage reads the
+// checkpoint of the previous ones and adds its own output to it.
+var pipelineStages = map[model.JobStage]struct {
+	run  func(srv *Service, ctx context.Context, job *model.Job) error
+	next model.JobStage
+}{
+	model.StageScrape: {(*Service).scrapeStage, model.StageMatch},
+	model.StageMatch:  {(*Service).matchStage, model.StageRecord},
+	model.StageRecord: {(*Service).recordStage, model.StageNotify},
+	model.StageNotify: {(*Service).notifyStage, model.StageDone},
+}
+
+// scrapeStage fails unless at least one page was scraped. An interrupted
+// scrape is not checkpointed, so the stage starts over on resume. The
+// anomalies of a failed scrape are kept, so a run that fails for good on
+// non-200 responses is still reported as degraded.
+func (srv *Service) scrapeStage(ctx context.Context, job *model.Job) error {
+	allScrapedItems, pagesScraped, scrapeErrors := srv.ScrapeItems(ctx)
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	job.Checkpoint.BadStatuses = 0
+	for _, err := range scrapeErrors {
+		var statusErr *scraper.StatusError
+		if errors.As(err, &statusErr) {
+			job.Checkpoint.BadStatuses++
+		}
+	}
+	job.Checkpoint.Scraped = allScrapedItems
+	job.Checkpoint.PagesScraped = pagesScraped
+	job.Checkpoint.Anomalies = srv.detectAnomalies(ctx, job.Checkpoint)
+
+	if pagesScraped == 0 && len(scrapeErrors) > 0 {
+		return fmt.Errorf("failed to scrape any page: %w", errors.Join(scrapeErrors...))
+	}
+
+	if len(scrapeErrors) > 0 {
+		slog.WarnContext(ctx, "Scrape completed with errors", "errors", len(scrapeErrors))
+	}
+	job.Checkpoint.Errors = nil
+	for _, err := range scrapeErrors {
+		job.Checkpoint.Errors = append(job.Checkpoint.Errors, err.Error())
+	}
+	return nil
+}
+
+// matchStage matches the scraped items and finds the new listings and deals
+// among them. It only reads the listings, so a retry finds the same ones.
+func (srv *Service) matchStage(ctx context.Context, job *model.Job) error {
+	matchedItems, batches, err := srv.FindMatchItems(ctx, job.Checkpoint.Scraped)
+	job.Checkpoint.LLMBatches = batches
+	if err != nil {
+		return fmt.Errorf("failed to find matched items: %w", err)
+	}
+
+	if err := srv.store.SaveRunItems(job.RunID, job.Checkpoint.Scraped, matchedItems); err != nil {
+		slog.ErrorContext(ctx, "Error saving run items", "err", err)
+	}
+
+	srv.annotateMarketPrices(ctx, matchedItems)
+	srv.annotateCosts(matchedItems)
+	if err := srv.markNewListings(matchedItems); err != nil {
+		slog.ErrorContext(ctx, "Error finding new listings", "err", err)
+	}
+	job.Checkpoint.Matched = matchedItems
+	job.Checkpoint.Deals = srv.newDeals(matchedItems)
+	return nil
+}
+
+// recordStage records the matched items as the listings and prices seen by
+// the run. A run whose prices were already recorded, by an attempt that
+// failed to checkpoint, is not recorded twice.
+func (srv *Service) recordStage(ctx context.Context, job *model.Job) error {
+	recorded, err := srv.store.RunPricesRecorded(job.RunID)
+	if err != nil {
+		return err
+	}
+	if recorded {
+		slog.WarnContext(ctx, "Listings of the run were already recorded")
+		return nil
+	}
+
+	drops, err := srv.store.RecordListings(job.RunID, time.Now(), job.Checkpoint.Matched)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error recording listings", "err", err)
+	}
+	markPriceDrops(job.Checkpoint.Matched, drops)
+	job.Checkpoint.Drops = drops
+	return nil
+}
+
+// notifyStage finishes the run, then sends its alerts and the results to the
+// chats that asked for them.
+func (srv *Service) notifyStage(ctx context.Context, job *model.Job) error {
+	run, err := srv.finishRun(ctx, job, nil)
+	if err != nil {
+		return err
+	}
+	srv.results.put(runResult{run: run, items: job.Checkpoint.Matched})
+
+	if !job.Checkpoint.Notified {
+		job.Checkpoint.Notified = true
+		if err := srv.store.SaveJobProgress(job); err != nil {
+			return err
+		}
+		if len(job.Checkpoint.Drops) > 0 {
+			srv.notifyPriceDrops(ctx, job.Checkpoint.Drops)
+		}
+		if len(job.Checkpoint.Deals) > 0 {
+			srv.notifyDeals(ctx, job.Checkpoint.Deals)
+		}
+	}
+
+	srv.replyRequesters(ctx, job)
+	return nil
+}
+
+// finishRun records the outcome of a job's run, including the error it
+// failed with, if any, and alerts when the run is degraded.
+func (srv *Service) finishRun(ctx context.Context, job *model.Job, failure error) (*model.Run, error) {
+	run, err := srv.store.Run(job.RunID)
+	if err != nil {
+		return nil, err
+	}
+
+	run.Errors = job.Checkpoint.Errors
+	if failure != nil {
+		run.Errors = append(run.Errors, failure.Error())
+	}
+	run.PagesScraped = job.Checkpoint.PagesScraped
+	run.ItemsFound = len(job.Checkpoint.Scraped)
+	run.LLMBatches = job.Checkpoint.LLMBatches
+	run.Matches = len(job.Checkpoint.Matched)
+	run.Anomalies = job.Checkpoint.Anomalies
+	run.FinishedAt = time.Now()
+	if err := srv.store.FinishRun(run); err != nil {
+		return nil, err
+	}
+
+	result := "ok"
+	switch {
+	case failure != nil:
+		result = "failed"
+	case run.Degraded():
+		result = "degraded"
+	}
+	metrics.Runs.WithLabelValues(string(run.Trigger), result).Inc()
+	metrics.RunMatches.WithLabelValues(string(run.Trigger)).Observe(float64(run.Matches))
+
+	slog.InfoContext(ctx, "Run finished",
+		"duration", run.FinishedAt.Sub(run.StartedAt).Round(time.Second),
+		"pages", run.PagesScraped, "items", run.ItemsFound, "matches", run.Matches, "errors", len(run.Errors))
+	srv.alertRunHealth(ctx, run)
+
+	return run, nil
+}
+
+// Runs returns up to limit runs, newest first, including the ones still in
+// progress.
+func (srv *Service) Runs(limit int) ([]model.Run, error) {
+	return srv.store.RecentRuns(limit)
+}
+
+// RunDetails returns a run with every item it scraped. Items that were not
+// matched have an empty MatchedName.
+func (srv *Service) RunDetails(id int64) (*model.Run, []model.MatchedItem, error) {
+	run, err := srv.store.Run(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	items, err := srv.store.RunItems(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	return run, items, nil
+}
+
+// RunDiff compares the matched items of the last two runs that completed
+// matching.
+func (srv *Service) RunDiff() (*model.RunDiff, error) {
+	runs, err := srv.store.LatestRuns(2)
+	if err != nil {
+		return nil, err
+	}
+	if len(runs) < 2 {
+		return nil, ErrNotEnoughRuns
+	}
+
+	current, previous := runs[0], runs[1]
+
+	currentItems, err := srv.store.RunItems(current.ID)
+	if err != nil {
+		return nil, err
+	}
+	previousItems, err := srv.store.RunItems(previous.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := diffRunItems(previousItems, currentItems)
+	diff.Previous = previous
+	diff.Current = current
+
+	return diff, nil
+}
+
+// diffRunItems reports changes among matched items. Presence is judged against
+// every scraped item, so a listing the LLM failed to match again is not
+// reported as gone.
+func diffRunItems(previousItems, currentItems []model.MatchedItem) *model.RunDiff {
+	previousByURL := make(map[string]model.MatchedItem, len(previousItems))
+	for _, item := range previousItems {
+		previousByURL[item.URL] = item
+	}
+	currentByURL := make(map[string]model.MatchedItem, len(currentItems))
+	for _, item := range currentItems {
+		currentByURL[item.URL] = item
+	}
+
+	diff := &model.RunDiff{}
+
+	for _, item := range currentItems {
+		previousItem, existed := previousByURL[item.URL]
+		if item.MatchedName == "" {
+			item.MatchedName = previousItem.MatchedName
+		}
+		if item.MatchedName == "" {
+			continue
+		}
+
+		switch {
+		case !existed:
+			diff.Appeared = append(diff.Appeared, item)
+		case previousItem.Price != item.Price:
+			diff.PriceChanged = append(diff.PriceChanged, model.PriceChange{
+				Item:     item,
+				OldPrice: previousItem.Price,
+			})
+		}
+	}
+
+	for _, item := range previousItems {
+		if _, exists := currentByURL[item.URL]; !exists && item.MatchedName != "" {
+			diff.Disappeared = append(diff.Disappeared, item)
+		}
+	}
+
+	return diff
+}
@@ -0,0 +1,38 @@
+package service
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/drifterz13/dino-noti/model"
+)
+
+// StartScheduler queues a pipeline run every configured interval until the
+// service shuts down. It does nothing when no interval is configured.
+func (srv *Service) StartScheduler() {
+	if srv.cfg.ScheduleInterval <= 0 {
+		return
+	}
+
+	slog.Info("Scheduling runs", "interval", srv.cfg.ScheduleInterval)
+
+	srv.background.Add(1)
+	go func() {
+		defer srv.background.Done()
+		ticker := time.NewTicker(srv.cfg.ScheduleInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+			case <-srv.stopping:
+				return
+			}
+
+			// Scheduled runs always scrape, unless a run is already queued.
+			if _, err := srv.EnqueueRun(srv.ctx, model.TriggerSchedule, nil); err != nil {
+				slog.Error("Error queueing scheduled run", "err", err)
+			}
+		}
+	}()
+}
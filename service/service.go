@@ -1,67 +1,278 @@
 package service
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
-	"os"
+	"slices"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
 	"github.com/drifterz13/dino-noti/config"
 	"github.com/drifterz13/dino-noti/line"
 	"github.com/drifterz13/dino-noti/llm"
+	"github.com/drifterz13/dino-noti/logging"
+	"github.com/drifterz13/dino-noti/metrics"
 	"github.com/drifterz13/dino-noti/model"
+	"github.com/drifterz13/dino-noti/notify"
 	"github.com/drifterz13/dino-noti/parser"
 	"github.com/drifterz13/dino-noti/scraper"
+	"github.com/drifterz13/dino-noti/store"
+	"github.com/drifterz13/dino-noti/tracing"
 )
 
+const llmBatchSize = 40
+
+var (
+	ErrNotFound     = store.ErrNotFound
+	ErrInvalidInput = errors.New("invalid input")
+)
+
+// shutdownGrace is how long aborted work gets to record its partial progress
+// once the shutdown deadline has passed.
+const shutdownGrace = 5 * time.Second
+
 type Service struct {
-	cfg *config.Config
+	cfg       *config.Config
+	store     *store.Store
+	results   resultCache
+	notifiers map[model.Channel]notify.Notifier
+	// redirect, when set, receives every notification in place of the
+	// subscribers' channels.
+	redirect notify.Notifier
+	// jobWake signals the workers that a job was queued.
+	jobWake chan struct{}
+	// startedAt is when the service was created, the reference for overdue
+	// scheduled runs until the first one starts.
+	startedAt time.Time
+
+	// ctx is cancelled to abort in-flight work, stopping is closed to stop
+	// starting new work, and background tracks the goroutines doing either.
+	ctx        context.Context
+	cancel     context.CancelFunc
+	stopping   chan struct{}
+	stopOnce   sync.Once
+	background sync.WaitGroup
 }
 
-func NewService(cfg *config.Config) *Service {
-	return &Service{
-		cfg: cfg,
+func NewService(cfg *config.Config, st *store.Store) *Service {
+	ctx, cancel := context.WithCancel(context.Background())
+	srv := &Service{
+		cfg:       cfg,
+		store:     st,
+		ctx:       ctx,
+		cancel:    cancel,
+		stopping:  make(chan struct{}),
+		jobWake:   make(chan struct{}, 1),
+		startedAt: time.Now(),
 	}
+	srv.notifiers = newNotifiers(srv)
+	return srv
 }
 
-func (srv *Service) ScrapeItems() ([]model.ScrapeItem, []error) {
-	fmt.Printf("Starting scrape for %s up to page %d...\n", srv.cfg.TargetURL, srv.cfg.MaxPages)
+// Shutdown stops the scheduler, digests and workers and waits for in-flight
+// work. When ctx is done first, the work is aborted and given a short grace
+// period to checkpoint and requeue its jobs.
+func (srv *Service) Shutdown(ctx context.Context) error {
+	srv.stopOnce.Do(func() { close(srv.stopping) })
 
+	done := make(chan struct{})
+	go func() {
+		srv.background.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		srv.cancel()
+		return nil
+	case <-ctx.Done():
+	}
+
+	slog.Warn("Shutdown deadline reached, aborting in-flight work")
+	srv.cancel()
+	select {
+	case <-done:
+	case <-time.After(shutdownGrace):
+		return errors.New("in-flight work did not stop after being aborted")
+	}
+	return fmt.Errorf("in-flight work aborted: %w", ctx.Err())
+}
+
+// ScrapeItems scrapes every enabled target and reports how many pages
+// succeeded. Listings found by several targets are kept once. It stops early
+// when ctx is done, returning what was scraped so far.
+func (srv *Service) ScrapeItems(ctx context.Context) ([]model.ScrapeItem, int, []error) {
+	targets, err := srv.ScrapeTargets()
+	if err != nil {
+		return nil, 0, []error{err}
+	}
+	return srv.Scrape(ctx, targets)
+}
+
+// Scrape scrapes the given targets like ScrapeItems.
+func (srv *Service) Scrape(ctx context.Context, targets []model.Target) ([]model.ScrapeItem, int, []error) {
 	ps := parser.NewBuyeeParser()
 
 	var allScrapedItems []model.ScrapeItem
+	seen := map[string]bool{}
+	pagesScraped := 0
+	scrapeErrors := []error{}
+
+	for _, target := range targets {
+		if ctx.Err() != nil {
+			break
+		}
+
+		items, pages, errs := srv.scrapeTarget(ctx, target, ps)
+		for _, item := range items {
+			if !seen[item.URL] {
+				seen[item.URL] = true
+				allScrapedItems = append(allScrapedItems, item)
+			}
+		}
+		pagesScraped += pages
+		scrapeErrors = append(scrapeErrors, errs...)
+	}
+
+	slog.InfoContext(ctx, "Finished scraping", "items", len(allScrapedItems), "pages", pagesScraped)
+
+	return allScrapedItems, pagesScraped, scrapeErrors
+}
+
+func (srv *Service) scrapeTarget(ctx context.Context, target model.Target, ps scraper.Parser) ([]model.ScrapeItem, int, []error) {
+	ctx, span := tracing.Start(ctx, "scrape.target", attribute.String("scrape.target", target.Name))
+	defer span.End()
+
+	ctx = logging.With(ctx, slog.String("target", target.Name))
+	slog.InfoContext(ctx, "Starting scrape", "url", target.URL, "max_pages", target.MaxPages)
+
+	var scrapedItems []model.ScrapeItem
+	pagesScraped := 0
 	scrapeErrors := []error{}
 
-	for pageNum := 1; pageNum <= srv.cfg.MaxPages; pageNum++ {
-		pageURL := fmt.Sprintf("%s&page=%d", srv.cfg.TargetURL, pageNum)
+pages:
+	for pageNum := 1; pageNum <= target.MaxPages; pageNum++ {
+		if err := ctx.Err(); err != nil {
+			scrapeErrors = append(scrapeErrors, fmt.Errorf("%s: stopped before page %d: %w", target.Name, pageNum, err))
+			break
+		}
 
-		itemsOnPage, err := scraper.ScrapePage(pageURL, ps)
+		pageURL, err := pageURL(target.URL, pageNum)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error scraping page %d (%s): %v\n", pageNum, pageURL, err)
-			scrapeErrors = append(scrapeErrors, err)
+			scrapeErrors = append(scrapeErrors, fmt.Errorf("%s: %w", target.Name, err))
+			break
+		}
+
+		itemsOnPage, err := scrapePage(ctx, target, pageURL, ps)
+		if err != nil {
+			slog.ErrorContext(ctx, "Error scraping page", "page", pageNum, "url", pageURL, "err", err)
+			scrapeErrors = append(scrapeErrors, fmt.Errorf("%s: %w", target.Name, err))
 			continue
 		}
-		allScrapedItems = append(allScrapedItems, itemsOnPage...)
-		time.Sleep(1 * time.Second)
+		scrapedItems = append(scrapedItems, itemsOnPage...)
+		pagesScraped++
+
+		select {
+		case <-time.After(1 * time.Second):
+		case <-ctx.Done():
+			if pageNum < target.MaxPages {
+				scrapeErrors = append(scrapeErrors, fmt.Errorf("%s: stopped after page %d: %w", target.Name, pageNum, ctx.Err()))
+			}
+			break pages
+		}
 	}
 
-	fmt.Printf("Finished scraping. Found a total of %d items.\n", len(allScrapedItems))
+	if pagesScraped > 0 {
+		scrape := model.TargetScrape{URL: target.URL, ScrapedAt: time.Now(), Pages: pagesScraped, Items: len(scrapedItems)}
+		if err := srv.store.RecordTargetScrape(scrape); err != nil {
+			slog.ErrorContext(ctx, "Error recording scrape", "err", err)
+		}
+	}
 
-	return allScrapedItems, scrapeErrors
+	span.SetAttributes(
+		attribute.Int("scrape.pages", pagesScraped),
+		attribute.Int("scrape.items", len(scrapedItems)),
+		attribute.Int("scrape.errors", len(scrapeErrors)),
+	)
+	return scrapedItems, pagesScraped, scrapeErrors
 }
 
-func (srv *Service) FindMatchItems(scrapedItems []model.ScrapeItem) ([]model.MatchedItem, error) {
-	llmClient, err := llm.NewLLMClient(srv.cfg.GeminiAPIKey)
+// scrapePage fetches and parses one page of a target, recording the fetch and
+// the number of items parsed.
+func scrapePage(ctx context.Context, target model.Target, pageURL string, ps scraper.Parser) ([]model.ScrapeItem, error) {
+	fetchCtx, span := tracing.Start(ctx, "scrape.fetch", attribute.String("url.full", pageURL))
+	start := time.Now()
+	htmlContent, err := scraper.FetchPage(fetchCtx, pageURL)
+	metrics.FetchDuration.WithLabelValues(target.Name).Observe(time.Since(start).Seconds())
+	code := fetchCode(err)
+	metrics.PagesFetched.WithLabelValues(target.Name, code).Inc()
+	if status, convErr := strconv.Atoi(code); convErr == nil {
+		span.SetAttributes(attribute.Int("http.response.status_code", status))
+	}
+	tracing.End(span, err)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch and parse %s: %w", pageURL, err)
+	}
+
+	parseCtx, span := tracing.Start(ctx, "scrape.parse")
+	items, err := ps.Parse(parseCtx, htmlContent)
+	span.SetAttributes(attribute.Int("scrape.items", len(items)))
+	tracing.End(span, err)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error initializing LLM client: %v\n", err)
-		os.Exit(1)
+		return nil, err
+	}
+	metrics.ItemsPerPage.WithLabelValues(target.Name).Observe(float64(len(items)))
+	if len(items) == 0 {
+		metrics.EmptyPages.WithLabelValues(target.Name).Inc()
 	}
+	return items, nil
+}
 
-	batchSize := 40
+func fetchCode(err error) string {
+	var statusErr *scraper.StatusError
+	switch {
+	case err == nil:
+		return strconv.Itoa(http.StatusOK)
+	case errors.As(err, &statusErr):
+		return strconv.Itoa(statusErr.Code)
+	default:
+		return "error"
+	}
+}
+
+// FindMatchItems matches scraped items against the search terms in batches
+// and reports how many batches were sent to the LLM. When a batch fails, the
+// matches of the other batches are still returned along with the first error.
+func (srv *Service) FindMatchItems(ctx context.Context, scrapedItems []model.ScrapeItem) (matched []model.MatchedItem, batches int, err error) {
+	ctx, span := tracing.Start(ctx, "match", attribute.Int("match.items", len(scrapedItems)))
+	defer func() {
+		span.SetAttributes(attribute.Int("match.matches", len(matched)))
+		tracing.End(span, err)
+	}()
+
+	llmClient, err := llm.NewLLMClient(ctx, srv.cfg.GeminiAPIKey)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to initialize LLM client: %w", err)
+	}
+
+	searchTerms := srv.searchTerms()
+
+	batchSize := llmBatchSize
 	numGoroutines := (len(scrapedItems) + batchSize - 1) / batchSize
 
-	var wg sync.WaitGroup
+	var (
+		wg   sync.WaitGroup
+		sent atomic.Int32
+	)
 	resultChan := make(chan []model.MatchedItem, numGoroutines)
 	errorChan := make(chan error, numGoroutines)
 
@@ -80,22 +291,39 @@ func (srv *Service) FindMatchItems(scrapedItems []model.ScrapeItem) ([]model.Mat
 				chunk = append(chunk, item.Name)
 			}
 
-			matches, err := llmClient.CheckMatches(chunk, srv.cfg.MyList)
+			batch := start/batchSize + 1
+			ctx, span := tracing.Start(ctx, "match.batch",
+				attribute.Int("match.batch", batch),
+				attribute.Int("match.items", len(chunk)),
+			)
+			defer span.End()
+
+			ctx = logging.With(ctx, slog.Int("batch", batch))
+			if ctx.Err() != nil {
+				errorChan <- ctx.Err()
+				return
+			}
+			sent.Add(1)
+			matches, err := llmClient.CheckMatches(ctx, chunk, searchTerms)
 			if err != nil {
+				tracing.Fail(span, err)
 				errorChan <- err
 				return
 			}
+			span.SetAttributes(attribute.Int("match.matches", len(matches)))
 
 			var chunkMatchedItems []model.MatchedItem
 			for _, matchedItem := range matches {
 				scrapedItem := findScrapedItemByName(scrapedItems[start:end], matchedItem.OriginalName)
 				if scrapedItem != nil {
 					chunkMatchedItems = append(chunkMatchedItems, model.MatchedItem{
+						AuctionID:    scrapedItem.AuctionID,
 						URL:          scrapedItem.URL,
 						Price:        scrapedItem.Price,
 						OriginalName: matchedItem.OriginalName,
 						MatchedName:  matchedItem.MatchedName,
 						ImageURL:     scrapedItem.ImageURL,
+						Auction:      scrapedItem.Auction,
 					})
 				}
 			}
@@ -116,43 +344,52 @@ func (srv *Service) FindMatchItems(scrapedItems []model.ScrapeItem) ([]model.Mat
 	}
 
 	if len(errorChan) > 0 {
-		return nil, <-errorChan // Return the first error encountered
+		return allMatchedItems, int(sent.Load()), <-errorChan // Return the first error encountered
 	}
 
-	return allMatchedItems, nil
+	return allMatchedItems, int(sent.Load()), nil
+}
+
+// MatchItems matches scraped items and annotates them with market prices and
+// costs, without recording anything.
+func (srv *Service) MatchItems(ctx context.Context, scrapedItems []model.ScrapeItem) ([]model.MatchedItem, error) {
+	matchedItems, _, err := srv.FindMatchItems(ctx, scrapedItems)
+	srv.annotateMarketPrices(ctx, matchedItems)
+	srv.annotateCosts(matchedItems)
+	return matchedItems, err
 }
 
 func (srv *Service) HandleLineMessageReq(w http.ResponseWriter, req *http.Request) {
-	lineBotClient, err := line.NewLineBotClient(srv.cfg)
+	ctx, span := tracing.Start(req.Context(), "webhook")
+	defer span.End()
+
+	lineBotClient, err := line.NewLineBotClient(ctx, srv.cfg)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error creating LINE Bot client: %v\n", err)
+		tracing.Fail(span, err)
+		slog.ErrorContext(ctx, "Error creating LINE Bot client", "err", err)
 		return
 	}
 	events, err := lineBotClient.ParseEvents(req)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error parsing LINE events: %v\n", err)
+		tracing.Fail(span, err)
+		metrics.WebhookRequests.WithLabelValues("rejected").Inc()
+		slog.WarnContext(ctx, "Error parsing LINE events", "err", err)
 		return
 	}
+	metrics.WebhookRequests.WithLabelValues("ok").Inc()
+	span.SetAttributes(attribute.Int("line.events", len(events)))
 
 	w.WriteHeader(http.StatusOK)
 
+	// Events are handled after the response, so they continue the trace in
+	// the service's context rather than the request's.
+	eventCtx := trace.ContextWithSpan(srv.ctx, span)
+	receivedAt := time.Now()
+	srv.background.Add(1)
 	go func() {
-		allScrapedItems, scrapeErrors := srv.ScrapeItems()
-		if len(scrapeErrors) > 0 {
-			fmt.Fprintf(os.Stderr, "Completed with %d scraping errors.\n", len(scrapeErrors))
-		}
-
-		matchedItems, err := srv.FindMatchItems(allScrapedItems)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error finding matched items: %v\n", err)
-			return
-		}
-
-		err = lineBotClient.HandleSendMessage(events, matchedItems)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error sending message: %v\n", err)
-		} else {
-			fmt.Println("Message sent successfully")
+		defer srv.background.Done()
+		for _, event := range events {
+			srv.handleEvent(eventCtx, event, receivedAt)
 		}
 	}()
 }
@@ -165,3 +402,21 @@ func findScrapedItemByName(scrapedItems []model.ScrapeItem, name string) *model.
 	}
 	return nil
 }
+
+// searchTerms combines the configured models with every model on a watchlist.
+func (srv *Service) searchTerms() []string {
+	terms := append([]string{}, srv.cfg.MyList...)
+
+	watched, err := srv.store.WatchedModels()
+	if err != nil {
+		slog.Error("Error loading watched models", "err", err)
+		return terms
+	}
+
+	for _, name := range watched {
+		if !slices.ContainsFunc(terms, func(term string) bool { return strings.EqualFold(term, name) }) {
+			terms = append(terms, name)
+		}
+	}
+	return terms
+}
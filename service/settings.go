@@ -0,0 +1,109 @@
+package service
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/drifterz13/dino-noti/command"
+	"github.com/drifterz13/dino-noti/i18n"
+	"github.com/drifterz13/dino-noti/line"
+	"github.com/drifterz13/dino-noti/model"
+)
+
+// handleSettings shows the subscriber's settings, or changes one of them
+// when called with arguments.
+func (srv *Service) handleSettings(req *commandRequest) error {
+	sub, err := srv.store.Subscriber(req.sourceID)
+	if err != nil {
+		return err
+	}
+
+	if req.args == "" {
+		return srv.replySettings(req, sub)
+	}
+
+	name, value, _ := strings.Cut(req.args, " ")
+	value = strings.TrimSpace(value)
+	prefs := sub.Preferences
+
+	switch strings.ToLower(name) {
+	case "language", "lang":
+		lang, ok := i18n.ParseLang(value)
+		if !ok {
+			return srv.reply(req, req.printer.T(i18n.UnknownLanguage, value))
+		}
+		prefs.Language = string(lang)
+	case "timezone", "tz":
+		if _, err := time.LoadLocation(value); err != nil || value == "" {
+			return srv.reply(req, req.printer.T(i18n.UnknownTimezone, value))
+		}
+		prefs.Timezone = value
+	case "quiet":
+		if strings.EqualFold(value, "off") {
+			prefs.QuietStart, prefs.QuietEnd = 0, 0
+			break
+		}
+		start, end, err := command.ParseQuietHours(value)
+		if err != nil {
+			return srv.reply(req, req.printer.T(i18n.SettingsUsage))
+		}
+		prefs.QuietStart, prefs.QuietEnd = start, end
+	case "digest":
+		mode := model.DigestMode(strings.ToLower(value))
+		if mode != model.DigestImmediate && mode != model.DigestHourly && mode != model.DigestDaily {
+			return srv.reply(req, req.printer.T(i18n.SettingsUsage))
+		}
+		prefs.Digest = mode
+	case "deals":
+		discount, err := strconv.ParseFloat(strings.TrimSuffix(value, "%"), 64)
+		if err != nil || discount < 0 || discount >= 100 {
+			return srv.reply(req, req.printer.T(i18n.SettingsUsage))
+		}
+		prefs.MinDiscount = discount
+	default:
+		return srv.reply(req, req.printer.T(i18n.SettingsUsage))
+	}
+
+	if err := srv.store.SetPreferences(req.sourceID, prefs); err != nil {
+		return err
+	}
+	sub.Preferences = prefs
+	req.printer = srv.printer(prefs)
+	req.location = srv.location(prefs)
+	return srv.replySettings(req, sub)
+}
+
+func (srv *Service) replySettings(req *commandRequest, sub *model.Subscriber) error {
+	watchlist, err := srv.store.Watchlist(req.sourceID)
+	if err != nil {
+		return err
+	}
+
+	p := req.printer
+	prefs := sub.Preferences
+
+	notifications := p.T(i18n.NotificationsOn)
+	if sub.Muted(time.Now()) {
+		notifications = p.T(i18n.NotificationsMuted, p.Time(sub.MutedUntil.In(req.location)))
+	}
+	quiet := p.T(i18n.Off)
+	if prefs.HasQuietHours() {
+		quiet = line.FormatQuietHours(prefs)
+	}
+
+	lines := []string{
+		p.T(i18n.SettingsHeader),
+		p.T(i18n.SettingsNotifications, notifications),
+		p.T(i18n.SettingsLanguage, p.T(i18n.LanguageName)),
+		p.T(i18n.SettingsTimezone, req.location),
+		p.T(i18n.SettingsQuietHours, quiet),
+		p.T(i18n.SettingsDelivery, line.DigestLabel(p, prefs.Digest)),
+		p.T(i18n.SettingsWatched, len(watchlist), len(srv.cfg.MyList)),
+		p.T(i18n.SettingsDeals, srv.cfg.DealPercentile, prefs.MinDiscount),
+	}
+
+	messages := line.TextMessages(strings.Join(lines, "\n"))
+	messages = append(messages, line.BuildSettingsForm(p, prefs, req.location.String()))
+	return srv.send(req, messages...)
+}
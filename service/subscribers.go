@@ -0,0 +1,84 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/drifterz13/dino-noti/i18n"
+	"github.com/drifterz13/dino-noti/model"
+)
+
+func (srv *Service) Subscribers() ([]model.Subscriber, error) {
+	return srv.store.Subscribers()
+}
+
+func (srv *Service) Subscriber(id string) (*model.Subscriber, error) {
+	return srv.store.Subscriber(id)
+}
+
+// Routes returns the channels a subscriber is notified on.
+func (srv *Service) Routes(subscriberID string) ([]model.Route, error) {
+	return srv.routes(subscriberID)
+}
+
+// UpdateSubscriber applies the fields set in the update and returns the
+// updated subscriber.
+func (srv *Service) UpdateSubscriber(id string, update model.SubscriberUpdate) (*model.Subscriber, error) {
+	if _, err := srv.store.Subscriber(id); err != nil {
+		return nil, err
+	}
+	if update.Preferences != nil {
+		if err := validatePreferences(*update.Preferences); err != nil {
+			return nil, err
+		}
+	}
+
+	if update.Active != nil {
+		if err := srv.store.SetActive(id, *update.Active); err != nil {
+			return nil, err
+		}
+	}
+	if update.MutedUntil != nil {
+		if err := srv.store.SetMutedUntil(id, *update.MutedUntil); err != nil {
+			return nil, err
+		}
+	}
+	if update.Preferences != nil {
+		if err := srv.store.SetPreferences(id, *update.Preferences); err != nil {
+			return nil, err
+		}
+	}
+
+	return srv.store.Subscriber(id)
+}
+
+// validatePreferences accepts what the settings command accepts. Empty
+// language and timezone fall back to the configured ones.
+func validatePreferences(prefs model.Preferences) error {
+	if prefs.Language != "" {
+		if _, ok := i18n.ParseLang(prefs.Language); !ok {
+			return fmt.Errorf("%w: unknown language %q", ErrInvalidInput, prefs.Language)
+		}
+	}
+	if prefs.Timezone != "" {
+		if _, err := time.LoadLocation(prefs.Timezone); err != nil {
+			return fmt.Errorf("%w: unknown timezone %q", ErrInvalidInput, prefs.Timezone)
+		}
+	}
+
+	const minutesPerDay = 24 * 60
+	if prefs.QuietStart < 0 || prefs.QuietStart >= minutesPerDay || prefs.QuietEnd < 0 || prefs.QuietEnd >= minutesPerDay {
+		return fmt.Errorf("%w: quiet hours must be minutes into the day", ErrInvalidInput)
+	}
+
+	switch prefs.Digest {
+	case model.DigestImmediate, model.DigestHourly, model.DigestDaily:
+	default:
+		return fmt.Errorf("%w: unknown digest mode %q", ErrInvalidInput, prefs.Digest)
+	}
+
+	if prefs.MinDiscount < 0 || prefs.MinDiscount >= 100 {
+		return fmt.Errorf("%w: minimum discount must be between 0 and 100", ErrInvalidInput)
+	}
+	return nil
+}
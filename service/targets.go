@@ -0,0 +1,120 @@
+package service
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/drifterz13/dino-noti/model"
+)
+
+// maxTargetPages keeps a single target from hammering Buyee.
+const maxTargetPages = 50
+
+func (srv *Service) Targets() ([]model.Target, error) {
+	return srv.store.Targets()
+}
+
+func (srv *Service) AddTarget(target *model.Target) error {
+	if err := validateTarget(target); err != nil {
+		return err
+	}
+	return srv.store.AddTarget(target)
+}
+
+// UpdateTarget replaces a target and returns it as stored.
+func (srv *Service) UpdateTarget(target model.Target) (*model.Target, error) {
+	if err := validateTarget(&target); err != nil {
+		return nil, err
+	}
+	if err := srv.store.UpdateTarget(target); err != nil {
+		return nil, err
+	}
+	return srv.store.Target(target.ID)
+}
+
+func (srv *Service) DeleteTarget(id int64) error {
+	return srv.store.DeleteTarget(id)
+}
+
+// ResolveTarget finds a target by ID or name. A search page URL is accepted
+// as an unsaved target.
+func (srv *Service) ResolveTarget(ref string) (model.Target, error) {
+	if strings.HasPrefix(ref, "http://") || strings.HasPrefix(ref, "https://") {
+		target := model.Target{Name: ref, URL: ref, MaxPages: srv.cfg.MaxPages, Enabled: true}
+		return target, validateTarget(&target)
+	}
+
+	if id, err := strconv.ParseInt(ref, 10, 64); err == nil {
+		target, err := srv.store.Target(id)
+		if err != nil {
+			return model.Target{}, err
+		}
+		return *target, nil
+	}
+
+	targets, err := srv.ScrapeTargets()
+	if err != nil {
+		return model.Target{}, err
+	}
+	all, err := srv.store.Targets()
+	if err != nil {
+		return model.Target{}, err
+	}
+	for _, target := range append(targets, all...) {
+		if strings.EqualFold(target.Name, ref) {
+			return target, nil
+		}
+	}
+	return model.Target{}, fmt.Errorf("unknown target %q: %w", ref, ErrNotFound)
+}
+
+// ScrapeTargets returns the enabled targets, or the configured one until any
+// target is added.
+func (srv *Service) ScrapeTargets() ([]model.Target, error) {
+	targets, err := srv.store.Targets()
+	if err != nil {
+		return nil, err
+	}
+	if len(targets) == 0 {
+		return []model.Target{{Name: "default", URL: srv.cfg.TargetURL, MaxPages: srv.cfg.MaxPages, Enabled: true}}, nil
+	}
+
+	var enabled []model.Target
+	for _, target := range targets {
+		if target.Enabled {
+			enabled = append(enabled, target)
+		}
+	}
+	return enabled, nil
+}
+
+func validateTarget(target *model.Target) error {
+	target.Name = strings.TrimSpace(target.Name)
+	if target.Name == "" {
+		return fmt.Errorf("%w: target name is required", ErrInvalidInput)
+	}
+
+	u, err := url.Parse(target.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: target URL must be an http or https URL", ErrInvalidInput)
+	}
+
+	if target.MaxPages < 1 || target.MaxPages > maxTargetPages {
+		return fmt.Errorf("%w: max pages must be between 1 and %d", ErrInvalidInput, maxTargetPages)
+	}
+	return nil
+}
+
+// pageURL sets the page number of a search results URL.
+func pageURL(targetURL string, page int) (string, error) {
+	u, err := url.Parse(targetURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid target URL %q: %w", targetURL, err)
+	}
+	query := u.Query()
+	query.Set("page", strconv.Itoa(page))
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
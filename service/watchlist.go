@@ -0,0 +1,63 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/drifterz13/dino-noti/model"
+)
+
+func (srv *Service) Watchlist(sourceID string) ([]model.WatchEntry, error) {
+	return srv.store.Watchlist(sourceID)
+}
+
+// Watchlists returns the watchlist entries of every chat.
+func (srv *Service) Watchlists() ([]model.WatchEntry, error) {
+	return srv.store.Watchlists()
+}
+
+// Watch adds a model to a chat's watchlist, spelled like the search term it
+// matches, if any. It reports false when the model was already on it.
+func (srv *Service) Watch(entry model.WatchEntry) (model.WatchEntry, bool, error) {
+	entry.Model = strings.TrimSpace(entry.Model)
+	if entry.SourceID == "" || entry.Model == "" {
+		return entry, false, fmt.Errorf("%w: source and model are required", ErrInvalidInput)
+	}
+
+	if _, err := srv.store.Subscriber(entry.SourceID); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return entry, false, fmt.Errorf("unknown chat %s: %w", entry.SourceID, err)
+		}
+		return entry, false, err
+	}
+
+	for _, term := range srv.searchTerms() {
+		if strings.EqualFold(term, entry.Model) {
+			entry.Model = term
+			break
+		}
+	}
+
+	added, err := srv.store.AddWatch(entry.SourceID, entry)
+	if err != nil {
+		return entry, false, err
+	}
+
+	// Return the stored entry, which keeps whoever added it first.
+	watchlist, err := srv.store.Watchlist(entry.SourceID)
+	if err != nil {
+		return entry, added, err
+	}
+	for _, stored := range watchlist {
+		if strings.EqualFold(stored.Model, entry.Model) {
+			return stored, added, nil
+		}
+	}
+	return entry, added, nil
+}
+
+// Unwatch reports whether the model was on the chat's watchlist.
+func (srv *Service) Unwatch(sourceID, name string) (bool, error) {
+	return srv.store.RemoveWatch(sourceID, name)
+}
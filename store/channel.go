@@ -0,0 +1,68 @@
+package store
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/drifterz13/dino-noti/model"
+)
+
+// AddRoute reports false when the subscriber already had the route.
+func (s *Store) AddRoute(subscriberID string, route model.Route) (bool, error) {
+	res, err := s.db.Exec(
+		`INSERT INTO subscriber_channels (subscriber_id, channel, target, created_at) VALUES (?, ?, ?, ?)
+		ON CONFLICT (subscriber_id, channel, target) DO NOTHING`,
+		subscriberID, route.Channel, route.Target, time.Now(),
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to add %s channel for %s: %w", route.Channel, subscriberID, err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to add %s channel for %s: %w", route.Channel, subscriberID, err)
+	}
+	return n > 0, nil
+}
+
+// RemoveRoutes removes the subscriber's routes on a channel, or only the one
+// to target when it is not empty. It returns how many were removed.
+func (s *Store) RemoveRoutes(subscriberID string, channel model.Channel, target string) (int64, error) {
+	res, err := s.db.Exec(
+		`DELETE FROM subscriber_channels WHERE subscriber_id = ? AND channel = ? AND (? = '' OR target = ?)`,
+		subscriberID, channel, target, target,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to remove %s channel for %s: %w", channel, subscriberID, err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to remove %s channel for %s: %w", channel, subscriberID, err)
+	}
+	return n, nil
+}
+
+// Routes returns the channels configured by a subscriber. Subscribers without
+// any are notified on LINE only, which callers decide.
+func (s *Store) Routes(subscriberID string) ([]model.Route, error) {
+	rows, err := s.db.Query(
+		`SELECT channel, target FROM subscriber_channels WHERE subscriber_id = ? ORDER BY created_at, channel`,
+		subscriberID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query channels of %s: %w", subscriberID, err)
+	}
+	defer rows.Close()
+
+	var routes []model.Route
+	for rows.Next() {
+		var route model.Route
+		if err := rows.Scan(&route.Channel, &route.Target); err != nil {
+			return nil, fmt.Errorf("failed to scan channel: %w", err)
+		}
+		routes = append(routes, route)
+	}
+
+	return routes, rows.Err()
+}
@@ -0,0 +1,134 @@
+package store
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/drifterz13/dino-noti/model"
+)
+
+func (s *Store) TrackListing(sourceID, auctionID string) error {
+	_, err := s.db.Exec(
+		`INSERT INTO tracked_listings (source_id, auction_id, created_at) VALUES (?, ?, ?)
+		ON CONFLICT (source_id, auction_id) DO NOTHING`,
+		sourceID, auctionID, time.Now(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to track listing %s for %s: %w", auctionID, sourceID, err)
+	}
+	return nil
+}
+
+// TrackedListings returns the listings a source tracks, most recent first.
+func (s *Store) TrackedListings(sourceID string) ([]model.MatchedItem, error) {
+	rows, err := s.db.Query(
+		`SELECT l.auction_id, l.url, l.name, l.matched_name, l.price, l.image_url
+		FROM tracked_listings t
+		JOIN listings l ON l.auction_id = t.auction_id
+		WHERE t.source_id = ?
+		ORDER BY t.created_at DESC`,
+		sourceID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tracked listings of %s: %w", sourceID, err)
+	}
+	defer rows.Close()
+
+	var items []model.MatchedItem
+	for rows.Next() {
+		var (
+			item  model.MatchedItem
+			price int
+		)
+		if err := rows.Scan(&item.AuctionID, &item.URL, &item.OriginalName, &item.MatchedName, &price, &item.ImageURL); err != nil {
+			return nil, fmt.Errorf("failed to scan tracked listing: %w", err)
+		}
+		item.Price = fmt.Sprintf("%d", price)
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
+
+// Trackers returns the sources tracking any of the given listings, keyed by
+// auction ID.
+func (s *Store) Trackers(auctionIDs []string) (map[string][]string, error) {
+	trackers := make(map[string][]string)
+	if len(auctionIDs) == 0 {
+		return trackers, nil
+	}
+
+	args := make([]any, len(auctionIDs))
+	for i, auctionID := range auctionIDs {
+		args[i] = auctionID
+	}
+	rows, err := s.db.Query(
+		`SELECT auction_id, source_id FROM tracked_listings
+		WHERE auction_id IN (?`+strings.Repeat(`, ?`, len(auctionIDs)-1)+`)`,
+		args...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query trackers: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var auctionID, sourceID string
+		if err := rows.Scan(&auctionID, &sourceID); err != nil {
+			return nil, fmt.Errorf("failed to scan tracker: %w", err)
+		}
+		trackers[auctionID] = append(trackers[auctionID], sourceID)
+	}
+	return trackers, rows.Err()
+}
+
+func (s *Store) IgnoreListing(sourceID, auctionID string) error {
+	_, err := s.db.Exec(
+		`INSERT INTO ignored_listings (source_id, auction_id, created_at) VALUES (?, ?, ?)
+		ON CONFLICT (source_id, auction_id) DO NOTHING`,
+		sourceID, auctionID, time.Now(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to ignore listing %s for %s: %w", auctionID, sourceID, err)
+	}
+	return nil
+}
+
+func (s *Store) IgnoreModel(sourceID, matchedName string, until time.Time) error {
+	_, err := s.db.Exec(
+		`INSERT INTO ignored_models (source_id, model, until) VALUES (?, ?, ?)
+		ON CONFLICT (source_id, model) DO UPDATE SET until = excluded.until`,
+		sourceID, matchedName, until,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to ignore model %s for %s: %w", matchedName, sourceID, err)
+	}
+	return nil
+}
+
+// ItemFilter loads the listings and models a source currently ignores.
+func (s *Store) ItemFilter(sourceID string, now time.Time) (model.ItemFilter, error) {
+	filter := model.ItemFilter{
+		IgnoredListings: make(map[string]bool),
+		IgnoredModels:   make(map[string]bool),
+	}
+
+	listings, err := s.queryStrings(`SELECT auction_id FROM ignored_listings WHERE source_id = ?`, sourceID)
+	if err != nil {
+		return filter, err
+	}
+	for _, auctionID := range listings {
+		filter.IgnoredListings[auctionID] = true
+	}
+
+	models, err := s.queryStrings(`SELECT model FROM ignored_models WHERE source_id = ? AND until > ?`, sourceID, now)
+	if err != nil {
+		return filter, err
+	}
+	for _, name := range models {
+		filter.IgnoredModels[name] = true
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,23 @@
+package store
+
+import (
+	"fmt"
+
+	"github.com/drifterz13/dino-noti/model"
+)
+
+func (s *Store) RecordDelivery(delivery *model.Delivery) error {
+	res, err := s.db.Exec(
+		`INSERT INTO deliveries (source_id, channel, mode, messages, error, created_at) VALUES (?, ?, ?, ?, ?, ?)`,
+		delivery.SourceID, delivery.Channel, delivery.Mode, delivery.Messages, delivery.Error, delivery.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record delivery to %s: %w", delivery.SourceID, err)
+	}
+
+	delivery.ID, err = res.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to read delivery id: %w", err)
+	}
+	return nil
+}
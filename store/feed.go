@@ -0,0 +1,48 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// FeedToken returns the feed token of a source, or ErrNotFound when it has
+// none yet.
+func (s *Store) FeedToken(sourceID string) (string, error) {
+	var token string
+	err := s.db.QueryRow(`SELECT token FROM feed_tokens WHERE source_id = ?`, sourceID).Scan(&token)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", ErrNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to query feed token of %s: %w", sourceID, err)
+	}
+	return token, nil
+}
+
+// SetFeedToken gives a source a new feed token, replacing its old one.
+func (s *Store) SetFeedToken(sourceID, token string) error {
+	_, err := s.db.Exec(
+		`INSERT INTO feed_tokens (source_id, token, created_at) VALUES (?, ?, ?)
+		ON CONFLICT (source_id) DO UPDATE SET token = excluded.token, created_at = excluded.created_at`,
+		sourceID, token, time.Now(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to set feed token of %s: %w", sourceID, err)
+	}
+	return nil
+}
+
+// FeedSource returns the source a feed token belongs to.
+func (s *Store) FeedSource(token string) (string, error) {
+	var sourceID string
+	err := s.db.QueryRow(`SELECT source_id FROM feed_tokens WHERE token = ?`, token).Scan(&sourceID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", ErrNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to query feed token: %w", err)
+	}
+	return sourceID, nil
+}
@@ -0,0 +1,64 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/drifterz13/dino-noti/model"
+)
+
+// Ping checks that the database answers queries.
+func (s *Store) Ping(ctx context.Context) error {
+	var one int
+	if err := s.db.QueryRowContext(ctx, `SELECT 1`).Scan(&one); err != nil {
+		return fmt.Errorf("failed to query database: %w", err)
+	}
+	return nil
+}
+
+// RecordTargetScrape saves a successful scrape of the target URL, replacing
+// the previous one.
+func (s *Store) RecordTargetScrape(scrape model.TargetScrape) error {
+	_, err := s.db.Exec(
+		`INSERT INTO target_scrapes (url, scraped_at, pages, items) VALUES (?, ?, ?, ?)
+		ON CONFLICT (url) DO UPDATE SET scraped_at = excluded.scraped_at, pages = excluded.pages, items = excluded.items`,
+		scrape.URL, scrape.ScrapedAt, scrape.Pages, scrape.Items,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record scrape of %s: %w", scrape.URL, err)
+	}
+	return nil
+}
+
+// TargetScrapes returns the last successful scrape of each target URL.
+func (s *Store) TargetScrapes(ctx context.Context) (map[string]model.TargetScrape, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT url, scraped_at, pages, items FROM target_scrapes`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query target scrapes: %w", err)
+	}
+	defer rows.Close()
+
+	scrapes := map[string]model.TargetScrape{}
+	for rows.Next() {
+		var scrape model.TargetScrape
+		if err := rows.Scan(&scrape.URL, &scrape.ScrapedAt, &scrape.Pages, &scrape.Items); err != nil {
+			return nil, fmt.Errorf("failed to scan target scrape: %w", err)
+		}
+		scrapes[scrape.URL] = scrape
+	}
+	return scrapes, rows.Err()
+}
+
+// LastRunStart returns when the latest run started, or the zero time when
+// there has been none.
+func (s *Store) LastRunStart(ctx context.Context) (time.Time, error) {
+	var startedAt time.Time
+	err := s.db.QueryRowContext(ctx, `SELECT started_at FROM runs ORDER BY id DESC LIMIT 1`).Scan(&startedAt)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return time.Time{}, fmt.Errorf("failed to query last run: %w", err)
+	}
+	return startedAt, nil
+}
@@ -0,0 +1,292 @@
+package store
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/drifterz13/dino-noti/model"
+)
+
+const jobColumns = `id, trigger, status, stage, attempts, run_id, checkpoint, last_error, run_after, created_at, updated_at, trace_parent`
+
+// EnqueueJob queues a pipeline job for the requester, if any. A job that has
+// not reached the notify stage yet is joined instead of queueing another, as
+// its results will be just as fresh; joined reports whether that happened.
+// A queued job waiting out a retry backoff is not joined.
+// A new job continues the trace of traceParent, if any.
+func (s *Store) EnqueueJob(trigger model.RunTrigger, requester *model.JobRequester, traceParent string) (id int64, joined bool, err error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	err = tx.QueryRow(
+		`SELECT id FROM jobs
+		WHERE stage IN (?, ?, ?) AND (status = ? OR (status = ? AND run_after <= ?))
+		ORDER BY id
+		LIMIT 1`,
+		model.StageScrape, model.StageMatch, model.StageRecord, model.JobRunning, model.JobQueued, now,
+	).Scan(&id)
+	switch {
+	case err == nil:
+		joined = true
+	case errors.Is(err, sql.ErrNoRows):
+		res, err := tx.Exec(
+			`INSERT INTO jobs (trigger, status, stage, run_after, created_at, updated_at, trace_parent) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			trigger, model.JobQueued, model.StageScrape, now, now, now, traceParent,
+		)
+		if err != nil {
+			return 0, false, fmt.Errorf("failed to enqueue job: %w", err)
+		}
+		if id, err = res.LastInsertId(); err != nil {
+			return 0, false, fmt.Errorf("failed to read job id: %w", err)
+		}
+	default:
+		return 0, false, fmt.Errorf("failed to query open jobs: %w", err)
+	}
+
+	if requester != nil {
+		_, err := tx.Exec(
+			`INSERT OR REPLACE INTO job_requesters (job_id, source_id, as_carousel, created_at) VALUES (?, ?, ?, ?)`,
+			id, requester.SourceID, requester.AsCarousel, time.Now(),
+		)
+		if err != nil {
+			return 0, false, fmt.Errorf("failed to add requester to job %d: %w", id, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, false, fmt.Errorf("failed to commit job %d: %w", id, err)
+	}
+	return id, joined, nil
+}
+
+// ClaimJob marks the oldest queued job that is due as running and returns it,
+// or nil when there is none.
+func (s *Store) ClaimJob(now time.Time) (*model.Job, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	job, err := scanJob(tx.QueryRow(
+		`SELECT `+jobColumns+` FROM jobs WHERE status = ? AND run_after <= ? ORDER BY id LIMIT 1`,
+		model.JobQueued, now,
+	))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.Exec(`UPDATE jobs SET status = ?, updated_at = ? WHERE id = ?`, model.JobRunning, now, job.ID); err != nil {
+		return nil, fmt.Errorf("failed to claim job %d: %w", job.ID, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit claim of job %d: %w", job.ID, err)
+	}
+
+	job.Status = model.JobRunning
+	job.UpdatedAt = now
+	return job, nil
+}
+
+// ClaimQueuedJob marks a specific queued job as running, even if it is not
+// due yet. It returns nil when the job is not queued, such as when another
+// process claimed it first.
+func (s *Store) ClaimQueuedJob(id int64, now time.Time) (*model.Job, error) {
+	res, err := s.db.Exec(
+		`UPDATE jobs SET status = ?, updated_at = ? WHERE id = ? AND status = ?`,
+		model.JobRunning, now, id, model.JobQueued,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim job %d: %w", id, err)
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		return nil, err
+	}
+	return s.Job(id)
+}
+
+func (s *Store) Job(id int64) (*model.Job, error) {
+	job, err := scanJob(s.db.QueryRow(`SELECT `+jobColumns+` FROM jobs WHERE id = ?`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("job %d: %w", id, ErrNotFound)
+	}
+	return job, err
+}
+
+// SaveJobProgress stores the stage, checkpoint and run of a running job.
+func (s *Store) SaveJobProgress(job *model.Job) error {
+	checkpointJSON, err := json.Marshal(job.Checkpoint)
+	if err != nil {
+		return fmt.Errorf("failed to encode checkpoint of job %d: %w", job.ID, err)
+	}
+
+	_, err = s.db.Exec(
+		`UPDATE jobs SET stage = ?, attempts = ?, run_id = ?, checkpoint = ?, updated_at = ? WHERE id = ?`,
+		job.Stage, job.Attempts, nullInt64(job.RunID), string(checkpointJSON), time.Now(), job.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save progress of job %d: %w", job.ID, err)
+	}
+	return nil
+}
+
+// RetryJob puts a failed job back in the queue, to resume from its stage once
+// runAfter has passed.
+func (s *Store) RetryJob(job *model.Job, runAfter time.Time) error {
+	_, err := s.db.Exec(
+		`UPDATE jobs SET status = ?, attempts = ?, last_error = ?, run_after = ?, updated_at = ? WHERE id = ?`,
+		model.JobQueued, job.Attempts, job.LastError, runAfter, time.Now(), job.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to requeue job %d: %w", job.ID, err)
+	}
+	return nil
+}
+
+func (s *Store) FinishJob(id int64) error {
+	_, err := s.db.Exec(
+		`UPDATE jobs SET status = ?, stage = ?, updated_at = ? WHERE id = ?`,
+		model.JobDone, model.StageDone, time.Now(), id,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to finish job %d: %w", id, err)
+	}
+	return nil
+}
+
+// KillJob gives up on a job, copying it to the dead letters.
+func (s *Store) KillJob(job *model.Job) error {
+	checkpointJSON, err := json.Marshal(job.Checkpoint)
+	if err != nil {
+		return fmt.Errorf("failed to encode checkpoint of job %d: %w", job.ID, err)
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	if _, err := tx.Exec(
+		`INSERT INTO dead_jobs (job_id, trigger, stage, attempts, error, checkpoint, failed_at) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		job.ID, job.Trigger, job.Stage, job.Attempts, job.LastError, string(checkpointJSON), now,
+	); err != nil {
+		return fmt.Errorf("failed to add dead letter for job %d: %w", job.ID, err)
+	}
+	if _, err := tx.Exec(
+		`UPDATE jobs SET status = ?, attempts = ?, last_error = ?, updated_at = ? WHERE id = ?`,
+		model.JobDead, job.Attempts, job.LastError, now, job.ID,
+	); err != nil {
+		return fmt.Errorf("failed to mark job %d as dead: %w", job.ID, err)
+	}
+
+	return tx.Commit()
+}
+
+// RequeueRunningJobs puts jobs left running by a previous process back in the
+// queue. It must only be called before any worker starts.
+func (s *Store) RequeueRunningJobs() (int64, error) {
+	res, err := s.db.Exec(
+		`UPDATE jobs SET status = ?, updated_at = ? WHERE status = ?`,
+		model.JobQueued, time.Now(), model.JobRunning,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to requeue running jobs: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+func (s *Store) JobRequesters(jobID int64) ([]model.JobRequester, error) {
+	rows, err := s.db.Query(
+		`SELECT source_id, as_carousel FROM job_requesters WHERE job_id = ? ORDER BY created_at`,
+		jobID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query requesters of job %d: %w", jobID, err)
+	}
+	defer rows.Close()
+
+	var requesters []model.JobRequester
+	for rows.Next() {
+		var r model.JobRequester
+		if err := rows.Scan(&r.SourceID, &r.AsCarousel); err != nil {
+			return nil, fmt.Errorf("failed to scan job requester: %w", err)
+		}
+		requesters = append(requesters, r)
+	}
+
+	return requesters, rows.Err()
+}
+
+// DeadJobs returns up to limit dead letters, newest first.
+func (s *Store) DeadJobs(limit int) ([]model.DeadJob, error) {
+	rows, err := s.db.Query(
+		`SELECT id, job_id, trigger, stage, attempts, error, checkpoint, failed_at
+		FROM dead_jobs
+		ORDER BY id DESC
+		LIMIT ?`,
+		limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query dead jobs: %w", err)
+	}
+	defer rows.Close()
+
+	var dead []model.DeadJob
+	for rows.Next() {
+		var (
+			d              model.DeadJob
+			checkpointJSON string
+		)
+		if err := rows.Scan(&d.ID, &d.JobID, &d.Trigger, &d.Stage, &d.Attempts, &d.Error, &checkpointJSON, &d.FailedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan dead job: %w", err)
+		}
+		if err := json.Unmarshal([]byte(checkpointJSON), &d.Checkpoint); err != nil {
+			return nil, fmt.Errorf("failed to decode checkpoint of dead job %d: %w", d.ID, err)
+		}
+		dead = append(dead, d)
+	}
+
+	return dead, rows.Err()
+}
+
+func scanJob(row scanner) (*model.Job, error) {
+	var (
+		job            model.Job
+		runID          sql.NullInt64
+		checkpointJSON string
+	)
+
+	err := row.Scan(
+		&job.ID, &job.Trigger, &job.Status, &job.Stage, &job.Attempts, &runID,
+		&checkpointJSON, &job.LastError, &job.RunAfter, &job.CreatedAt, &job.UpdatedAt, &job.TraceParent,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan job: %w", err)
+	}
+
+	job.RunID = runID.Int64
+	if err := json.Unmarshal([]byte(checkpointJSON), &job.Checkpoint); err != nil {
+		return nil, fmt.Errorf("failed to decode checkpoint of job %d: %w", job.ID, err)
+	}
+
+	return &job, nil
+}
+
+func nullInt64(v int64) sql.NullInt64 {
+	return sql.NullInt64{Int64: v, Valid: v != 0}
+}
@@ -0,0 +1,277 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/drifterz13/dino-noti/model"
+)
+
+// RecordListings upserts matched items into the listing store, appends their
+// prices to the price history and reports listings that got cheaper, either
+// by a price change or by being relisted below the earlier listing's price.
+func (s *Store) RecordListings(runID int64, observedAt time.Time, items []model.MatchedItem) ([]model.PriceDrop, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var drops []model.PriceDrop
+	for _, item := range items {
+		if item.AuctionID == "" {
+			continue
+		}
+		price, err := model.ParsePrice(item.Price)
+		if err != nil {
+			continue
+		}
+
+		var previousPrice int
+		err = tx.QueryRow(`SELECT price FROM listings WHERE auction_id = ?`, item.AuctionID).Scan(&previousPrice)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			relistOf, relistPrice, err := findRelist(tx, item)
+			if err != nil {
+				return nil, err
+			}
+			if relistOf != "" && price < relistPrice {
+				drops = append(drops, model.PriceDrop{Item: item, OldPrice: relistPrice, NewPrice: price, RelistOf: relistOf})
+			}
+		case err != nil:
+			return nil, fmt.Errorf("failed to look up listing %s: %w", item.AuctionID, err)
+		case price < previousPrice:
+			drops = append(drops, model.PriceDrop{Item: item, OldPrice: previousPrice, NewPrice: price})
+		}
+
+		_, err = tx.Exec(
+			`INSERT INTO listings (auction_id, url, name, image_url, matched_name, price, first_seen_at, last_seen_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT (auction_id) DO UPDATE SET
+				url = excluded.url,
+				name = excluded.name,
+				image_url = excluded.image_url,
+				matched_name = excluded.matched_name,
+				price = excluded.price,
+				last_seen_at = excluded.last_seen_at`,
+			item.AuctionID, item.URL, item.OriginalName, item.ImageURL, item.MatchedName, price, observedAt, observedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to save listing %s: %w", item.AuctionID, err)
+		}
+
+		_, err = tx.Exec(
+			`INSERT INTO price_history (auction_id, run_id, price, observed_at) VALUES (?, ?, ?, ?)`,
+			item.AuctionID, runID, price, observedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to save price of listing %s: %w", item.AuctionID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit listings: %w", err)
+	}
+
+	return drops, nil
+}
+
+// RunPricesRecorded reports whether RecordListings already saved prices for
+// the run.
+func (s *Store) RunPricesRecorded(runID int64) (bool, error) {
+	var recorded bool
+	err := s.db.QueryRow(`SELECT EXISTS (SELECT 1 FROM price_history WHERE run_id = ?)`, runID).Scan(&recorded)
+	if err != nil {
+		return false, fmt.Errorf("failed to look up prices of run %d: %w", runID, err)
+	}
+	return recorded, nil
+}
+
+// findRelist looks for the most recently seen listing with the same title and
+// canonical model, which is how relisted auctions show up on Buyee.
+func findRelist(tx *sql.Tx, item model.MatchedItem) (string, int, error) {
+	var (
+		auctionID string
+		price     int
+	)
+	err := tx.QueryRow(
+		`SELECT auction_id, price FROM listings
+		WHERE name = ? AND matched_name = ? AND auction_id != ?
+		ORDER BY last_seen_at DESC
+		LIMIT 1`,
+		item.OriginalName, item.MatchedName, item.AuctionID,
+	).Scan(&auctionID, &price)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", 0, nil
+	}
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to look up relist of %s: %w", item.AuctionID, err)
+	}
+
+	return auctionID, price, nil
+}
+
+// ModelPrices returns the distinct prices observed per listing of a canonical
+// model since the given time.
+func (s *Store) ModelPrices(matchedName string, since time.Time) ([]model.ListingPrice, error) {
+	rows, err := s.db.Query(
+		`SELECT DISTINCT ph.auction_id, l.name, ph.price
+		FROM price_history ph
+		JOIN listings l ON l.auction_id = ph.auction_id
+		WHERE l.matched_name = ? AND ph.observed_at >= ?`,
+		matchedName, since,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query prices of %s: %w", matchedName, err)
+	}
+	defer rows.Close()
+
+	var prices []model.ListingPrice
+	for rows.Next() {
+		var price model.ListingPrice
+		if err := rows.Scan(&price.AuctionID, &price.Name, &price.Price); err != nil {
+			return nil, fmt.Errorf("failed to scan price: %w", err)
+		}
+		prices = append(prices, price)
+	}
+
+	return prices, rows.Err()
+}
+
+// ExistingListings reports which of the given auction IDs are already stored.
+func (s *Store) ExistingListings(auctionIDs []string) (map[string]bool, error) {
+	existing := make(map[string]bool)
+	for _, auctionID := range auctionIDs {
+		var found int
+		err := s.db.QueryRow(`SELECT 1 FROM listings WHERE auction_id = ?`, auctionID).Scan(&found)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up listing %s: %w", auctionID, err)
+		}
+		existing[auctionID] = true
+	}
+	return existing, nil
+}
+
+// KnownModels returns every canonical model that has recorded listings.
+func (s *Store) KnownModels() ([]string, error) {
+	return s.queryStrings(`SELECT DISTINCT matched_name FROM listings ORDER BY matched_name`)
+}
+
+const listingColumns = `auction_id, url, name, image_url, matched_name, price, first_seen_at, last_seen_at`
+
+// Listings returns the listings last seen within the filter's time range,
+// most recent first.
+func (s *Store) Listings(filter model.ListingFilter) ([]model.Listing, error) {
+	query := `SELECT ` + listingColumns + ` FROM listings WHERE 1 = 1`
+	var args []any
+	if filter.Model != "" {
+		query += ` AND matched_name = ? COLLATE NOCASE`
+		args = append(args, filter.Model)
+	}
+	if len(filter.Models) > 0 {
+		query += ` AND matched_name COLLATE NOCASE IN (?` + strings.Repeat(`, ?`, len(filter.Models)-1) + `)`
+		for _, name := range filter.Models {
+			args = append(args, name)
+		}
+	}
+	if !filter.Since.IsZero() {
+		query += ` AND last_seen_at >= ?`
+		args = append(args, filter.Since)
+	}
+	if !filter.Until.IsZero() {
+		query += ` AND last_seen_at < ?`
+		args = append(args, filter.Until)
+	}
+	query += ` ORDER BY last_seen_at DESC, auction_id`
+	if filter.Limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, filter.Limit)
+	}
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query listings: %w", err)
+	}
+	defer rows.Close()
+
+	var listings []model.Listing
+	for rows.Next() {
+		listing, err := scanListing(rows)
+		if err != nil {
+			return nil, err
+		}
+		listings = append(listings, *listing)
+	}
+
+	return listings, rows.Err()
+}
+
+func (s *Store) Listing(auctionID string) (*model.Listing, error) {
+	listing, err := scanListing(s.db.QueryRow(`SELECT `+listingColumns+` FROM listings WHERE auction_id = ?`, auctionID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return listing, err
+}
+
+// PriceHistory returns every price observed for a listing, oldest first.
+func (s *Store) PriceHistory(auctionID string) ([]model.PricePoint, error) {
+	return s.queryPricePoints(
+		`SELECT auction_id, run_id, price, observed_at FROM price_history WHERE auction_id = ? ORDER BY observed_at`,
+		auctionID,
+	)
+}
+
+// ModelPriceHistory returns every price observed for the listings of a
+// canonical model since the given time, oldest first.
+func (s *Store) ModelPriceHistory(matchedName string, since time.Time) ([]model.PricePoint, error) {
+	return s.queryPricePoints(
+		`SELECT ph.auction_id, ph.run_id, ph.price, ph.observed_at
+		FROM price_history ph
+		JOIN listings l ON l.auction_id = ph.auction_id
+		WHERE l.matched_name = ? AND ph.observed_at >= ?
+		ORDER BY ph.observed_at`,
+		matchedName, since,
+	)
+}
+
+func (s *Store) queryPricePoints(query string, args ...any) ([]model.PricePoint, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query price history: %w", err)
+	}
+	defer rows.Close()
+
+	var points []model.PricePoint
+	for rows.Next() {
+		var (
+			p     model.PricePoint
+			runID sql.NullInt64
+		)
+		if err := rows.Scan(&p.AuctionID, &runID, &p.Price, &p.ObservedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan price: %w", err)
+		}
+		p.RunID = runID.Int64
+		points = append(points, p)
+	}
+
+	return points, rows.Err()
+}
+
+func scanListing(row scanner) (*model.Listing, error) {
+	var l model.Listing
+	err := row.Scan(&l.AuctionID, &l.URL, &l.Name, &l.ImageURL, &l.MatchedName, &l.Price, &l.FirstSeenAt, &l.LastSeenAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan listing: %w", err)
+	}
+	return &l, nil
+}
@@ -0,0 +1,69 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/drifterz13/dino-noti/model"
+)
+
+// QueueNotification holds a notification for the subscriber's next digest.
+func (s *Store) QueueNotification(subscriberID string, n model.Notification) error {
+	notificationJSON, err := json.Marshal(n)
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %w", err)
+	}
+
+	_, err = s.db.Exec(
+		`INSERT INTO pending_notifications (subscriber_id, notification, created_at) VALUES (?, ?, ?)`,
+		subscriberID, string(notificationJSON), time.Now(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to queue notification for %s: %w", subscriberID, err)
+	}
+	return nil
+}
+
+// PendingNotifications returns the subscriber's queued notifications, oldest
+// first.
+func (s *Store) PendingNotifications(subscriberID string) ([]model.PendingNotification, error) {
+	rows, err := s.db.Query(
+		`SELECT id, notification, created_at FROM pending_notifications WHERE subscriber_id = ? ORDER BY id`,
+		subscriberID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query pending notifications of %s: %w", subscriberID, err)
+	}
+	defer rows.Close()
+
+	var pending []model.PendingNotification
+	for rows.Next() {
+		var (
+			p                model.PendingNotification
+			notificationJSON string
+		)
+		if err := rows.Scan(&p.ID, &notificationJSON, &p.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan pending notification: %w", err)
+		}
+		if err := json.Unmarshal([]byte(notificationJSON), &p.Notification); err != nil {
+			return nil, fmt.Errorf("failed to decode pending notification %d: %w", p.ID, err)
+		}
+		pending = append(pending, p)
+	}
+
+	return pending, rows.Err()
+}
+
+// ClearPendingNotifications removes the subscriber's queued notifications up
+// to and including throughID, once they were sent in a digest.
+func (s *Store) ClearPendingNotifications(subscriberID string, throughID int64) error {
+	_, err := s.db.Exec(
+		`DELETE FROM pending_notifications WHERE subscriber_id = ? AND id <= ?`,
+		subscriberID, throughID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to clear pending notifications of %s: %w", subscriberID, err)
+	}
+	return nil
+}
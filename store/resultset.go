@@ -0,0 +1,63 @@
+package store
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/drifterz13/dino-noti/model"
+)
+
+// resultSetTTL bounds how long "More results" buttons keep working.
+const resultSetTTL = 7 * 24 * time.Hour
+
+// SaveResultSet stores the items sent to a source so later pages can be
+// served without running the pipeline again.
+func (s *Store) SaveResultSet(sourceID string, items []model.MatchedItem) (int64, error) {
+	itemsJSON, err := json.Marshal(items)
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode result set: %w", err)
+	}
+
+	now := time.Now()
+	if _, err := s.db.Exec(`DELETE FROM result_sets WHERE created_at < ?`, now.Add(-resultSetTTL)); err != nil {
+		return 0, fmt.Errorf("failed to prune result sets: %w", err)
+	}
+
+	res, err := s.db.Exec(
+		`INSERT INTO result_sets (source_id, items, created_at) VALUES (?, ?, ?)`,
+		sourceID, string(itemsJSON), now,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to save result set: %w", err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read result set id: %w", err)
+	}
+	return id, nil
+}
+
+// ResultSet returns the items of a result set owned by the source.
+func (s *Store) ResultSet(id int64, sourceID string) ([]model.MatchedItem, error) {
+	var itemsJSON string
+	err := s.db.QueryRow(
+		`SELECT items FROM result_sets WHERE id = ? AND source_id = ?`,
+		id, sourceID,
+	).Scan(&itemsJSON)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load result set %d: %w", id, err)
+	}
+
+	var items []model.MatchedItem
+	if err := json.Unmarshal([]byte(itemsJSON), &items); err != nil {
+		return nil, fmt.Errorf("failed to decode result set %d: %w", id, err)
+	}
+	return items, nil
+}
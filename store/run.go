@@ -0,0 +1,246 @@
+package store
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/drifterz13/dino-noti/model"
+)
+
+func (s *Store) CreateRun(run *model.Run) error {
+	res, err := s.db.Exec(
+		`INSERT INTO runs (started_at, trigger) VALUES (?, ?)`,
+		run.StartedAt, run.Trigger,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create run: %w", err)
+	}
+
+	run.ID, err = res.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to read run id: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Store) FinishRun(run *model.Run) error {
+	errorsJSON, err := encodeStrings(run.Errors)
+	if err != nil {
+		return fmt.Errorf("failed to encode run errors: %w", err)
+	}
+	anomaliesJSON, err := encodeStrings(run.Anomalies)
+	if err != nil {
+		return fmt.Errorf("failed to encode run anomalies: %w", err)
+	}
+
+	_, err = s.db.Exec(
+		`UPDATE runs
+		SET finished_at = ?, pages_scraped = ?, items_found = ?, llm_batches = ?, matches = ?, errors = ?, anomalies = ?
+		WHERE id = ?`,
+		run.FinishedAt, run.PagesScraped, run.ItemsFound, run.LLMBatches, run.Matches, errorsJSON, anomaliesJSON, run.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to finish run %d: %w", run.ID, err)
+	}
+
+	return nil
+}
+
+// encodeStrings encodes a nil slice as an empty JSON array.
+func encodeStrings(values []string) (string, error) {
+	if values == nil {
+		values = []string{}
+	}
+	encoded, err := json.Marshal(values)
+	return string(encoded), err
+}
+
+// HealthyItemCounts returns the items found by up to limit finished runs that
+// scraped at least one page and were not degraded, newest first.
+func (s *Store) HealthyItemCounts(limit int) ([]int, error) {
+	rows, err := s.db.Query(
+		`SELECT items_found FROM runs
+		WHERE finished_at IS NOT NULL AND pages_scraped > 0 AND anomalies = '[]'
+		ORDER BY id DESC
+		LIMIT ?`,
+		limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query item counts: %w", err)
+	}
+	defer rows.Close()
+
+	var counts []int
+	for rows.Next() {
+		var count int
+		if err := rows.Scan(&count); err != nil {
+			return nil, fmt.Errorf("failed to scan item count: %w", err)
+		}
+		counts = append(counts, count)
+	}
+	return counts, rows.Err()
+}
+
+// SaveRunItems stores a snapshot of every scraped item of a run, marking the
+// ones the LLM matched against the watchlist.
+func (s *Store) SaveRunItems(runID int64, scrapedItems []model.ScrapeItem, matchedItems []model.MatchedItem) error {
+	matchedNames := make(map[string]string, len(matchedItems))
+	for _, item := range matchedItems {
+		matchedNames[item.URL] = item.MatchedName
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(
+		`INSERT OR REPLACE INTO run_items (run_id, url, name, price, image_url, matched_name)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to prepare run item insert: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, item := range scrapedItems {
+		if _, err := stmt.Exec(runID, item.URL, item.Name, item.Price, item.ImageURL, matchedNames[item.URL]); err != nil {
+			return fmt.Errorf("failed to save run item %s: %w", item.URL, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (s *Store) Run(id int64) (*model.Run, error) {
+	run, err := scanRun(s.db.QueryRow(
+		`SELECT id, started_at, finished_at, trigger, pages_scraped, items_found, llm_batches, matches, errors, anomalies
+		FROM runs
+		WHERE id = ?`,
+		id,
+	))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return run, err
+}
+
+// LatestRuns returns up to limit finished runs that saved their items, newest
+// first. Runs that failed before matching have no items to compare against.
+func (s *Store) LatestRuns(limit int) ([]model.Run, error) {
+	return s.queryRuns(
+		`SELECT id, started_at, finished_at, trigger, pages_scraped, items_found, llm_batches, matches, errors, anomalies
+		FROM runs
+		WHERE finished_at IS NOT NULL
+			AND EXISTS (SELECT 1 FROM run_items WHERE run_items.run_id = runs.id)
+		ORDER BY id DESC
+		LIMIT ?`,
+		limit,
+	)
+}
+
+// PreviousRun returns the latest finished run started before the given one.
+func (s *Store) PreviousRun(id int64) (*model.Run, error) {
+	run, err := scanRun(s.db.QueryRow(
+		`SELECT id, started_at, finished_at, trigger, pages_scraped, items_found, llm_batches, matches, errors, anomalies
+		FROM runs
+		WHERE finished_at IS NOT NULL AND id < ?
+		ORDER BY id DESC
+		LIMIT 1`,
+		id,
+	))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return run, err
+}
+
+// RecentRuns returns up to limit runs, newest first, including the ones
+// still in progress.
+func (s *Store) RecentRuns(limit int) ([]model.Run, error) {
+	return s.queryRuns(
+		`SELECT id, started_at, finished_at, trigger, pages_scraped, items_found, llm_batches, matches, errors, anomalies
+		FROM runs
+		ORDER BY id DESC
+		LIMIT ?`,
+		limit,
+	)
+}
+
+func (s *Store) queryRuns(query string, args ...any) ([]model.Run, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query runs: %w", err)
+	}
+	defer rows.Close()
+
+	var runs []model.Run
+	for rows.Next() {
+		run, err := scanRun(rows)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, *run)
+	}
+
+	return runs, rows.Err()
+}
+
+// RunItems returns every item scraped during a run. Items that were not
+// matched have an empty MatchedName.
+func (s *Store) RunItems(runID int64) ([]model.MatchedItem, error) {
+	rows, err := s.db.Query(
+		`SELECT url, name, price, image_url, matched_name FROM run_items WHERE run_id = ?`,
+		runID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query items of run %d: %w", runID, err)
+	}
+	defer rows.Close()
+
+	var items []model.MatchedItem
+	for rows.Next() {
+		var item model.MatchedItem
+		if err := rows.Scan(&item.URL, &item.OriginalName, &item.Price, &item.ImageURL, &item.MatchedName); err != nil {
+			return nil, fmt.Errorf("failed to scan run item: %w", err)
+		}
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanRun(row scanner) (*model.Run, error) {
+	var (
+		run           model.Run
+		finishedAt    sql.NullTime
+		errorsJSON    string
+		anomaliesJSON string
+	)
+
+	err := row.Scan(
+		&run.ID, &run.StartedAt, &finishedAt, &run.Trigger,
+		&run.PagesScraped, &run.ItemsFound, &run.LLMBatches, &run.Matches, &errorsJSON, &anomaliesJSON,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan run: %w", err)
+	}
+
+	run.FinishedAt = finishedAt.Time
+	if err := json.Unmarshal([]byte(errorsJSON), &run.Errors); err != nil {
+		return nil, fmt.Errorf("failed to decode errors of run %d: %w", run.ID, err)
+	}
+	if err := json.Unmarshal([]byte(anomaliesJSON), &run.Anomalies); err != nil {
+		return nil, fmt.Errorf("failed to decode anomalies of run %d: %w", run.ID, err)
+	}
+
+	return &run, nil
+}
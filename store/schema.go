@@ -0,0 +1,217 @@
+package store
+
+// migrations are applied in order and tracked with PRAGMA user_version.
+// Never edit an existing entry, append a new one instead.
+var migrations = []string{
+	`
+CREATE TABLE runs (
+	id            INTEGER PRIMARY KEY AUTOINCREMENT,
+	started_at    TIMESTAMP NOT NULL,
+	finished_at   TIMESTAMP,
+	trigger       TEXT NOT NULL,
+	pages_scraped INTEGER NOT NULL DEFAULT 0,
+	items_found   INTEGER NOT NULL DEFAULT 0,
+	llm_batches   INTEGER NOT NULL DEFAULT 0,
+	matches       INTEGER NOT NULL DEFAULT 0,
+	errors        TEXT NOT NULL DEFAULT '[]'
+);
+
+CREATE TABLE run_items (
+	run_id       INTEGER NOT NULL REFERENCES runs(id) ON DELETE CASCADE,
+	url          TEXT NOT NULL,
+	name         TEXT NOT NULL,
+	price        TEXT NOT NULL,
+	image_url    TEXT NOT NULL,
+	matched_name TEXT NOT NULL DEFAULT '',
+	PRIMARY KEY (run_id, url)
+);
+`,
+	`
+CREATE TABLE listings (
+	auction_id    TEXT PRIMARY KEY,
+	url           TEXT NOT NULL,
+	name          TEXT NOT NULL,
+	image_url     TEXT NOT NULL,
+	matched_name  TEXT NOT NULL,
+	price         INTEGER NOT NULL,
+	first_seen_at TIMESTAMP NOT NULL,
+	last_seen_at  TIMESTAMP NOT NULL
+);
+
+CREATE INDEX listings_matched_name ON listings (matched_name);
+CREATE INDEX listings_name ON listings (name);
+
+CREATE TABLE price_history (
+	auction_id  TEXT NOT NULL REFERENCES listings(auction_id) ON DELETE CASCADE,
+	run_id      INTEGER REFERENCES runs(id) ON DELETE SET NULL,
+	price       INTEGER NOT NULL,
+	observed_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX price_history_auction_id ON price_history (auction_id, observed_at);
+
+CREATE TABLE subscribers (
+	id         TEXT PRIMARY KEY,
+	kind       TEXT NOT NULL,
+	created_at TIMESTAMP NOT NULL
+);
+`,
+	`
+CREATE TABLE watchlist (
+	source_id  TEXT NOT NULL,
+	model      TEXT NOT NULL,
+	created_at TIMESTAMP NOT NULL,
+	PRIMARY KEY (source_id, model)
+);
+
+ALTER TABLE subscribers ADD COLUMN muted_until TIMESTAMP;
+`,
+	`
+ALTER TABLE subscribers ADD COLUMN active INTEGER NOT NULL DEFAULT 1;
+`,
+	`
+CREATE TABLE tracked_listings (
+	source_id  TEXT NOT NULL,
+	auction_id TEXT NOT NULL,
+	created_at TIMESTAMP NOT NULL,
+	PRIMARY KEY (source_id, auction_id)
+);
+
+CREATE TABLE ignored_listings (
+	source_id  TEXT NOT NULL,
+	auction_id TEXT NOT NULL,
+	created_at TIMESTAMP NOT NULL,
+	PRIMARY KEY (source_id, auction_id)
+);
+
+CREATE TABLE ignored_models (
+	source_id TEXT NOT NULL,
+	model     TEXT NOT NULL,
+	until     TIMESTAMP NOT NULL,
+	PRIMARY KEY (source_id, model)
+);
+`,
+	`
+CREATE TABLE result_sets (
+	id         INTEGER PRIMARY KEY AUTOINCREMENT,
+	source_id  TEXT NOT NULL,
+	items      TEXT NOT NULL,
+	created_at TIMESTAMP NOT NULL
+);
+`,
+	`
+CREATE TABLE deliveries (
+	id         INTEGER PRIMARY KEY AUTOINCREMENT,
+	source_id  TEXT NOT NULL,
+	mode       TEXT NOT NULL,
+	messages   INTEGER NOT NULL,
+	error      TEXT NOT NULL DEFAULT '',
+	created_at TIMESTAMP NOT NULL
+);
+`,
+	`
+ALTER TABLE watchlist ADD COLUMN added_by TEXT NOT NULL DEFAULT '';
+ALTER TABLE watchlist ADD COLUMN added_by_name TEXT NOT NULL DEFAULT '';
+`,
+	`
+CREATE TABLE subscriber_channels (
+	subscriber_id TEXT NOT NULL REFERENCES subscribers (id),
+	channel       TEXT NOT NULL,
+	target        TEXT NOT NULL,
+	created_at    TIMESTAMP NOT NULL,
+	PRIMARY KEY (subscriber_id, channel, target)
+);
+
+ALTER TABLE deliveries ADD COLUMN channel TEXT NOT NULL DEFAULT 'line';
+`,
+	`
+ALTER TABLE subscribers ADD COLUMN timezone TEXT NOT NULL DEFAULT '';
+ALTER TABLE subscribers ADD COLUMN quiet_start INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE subscribers ADD COLUMN quiet_end INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE subscribers ADD COLUMN digest TEXT NOT NULL DEFAULT 'immediate';
+ALTER TABLE subscribers ADD COLUMN min_discount REAL NOT NULL DEFAULT 0;
+ALTER TABLE subscribers ADD COLUMN last_digest_at TIMESTAMP;
+
+CREATE TABLE pending_notifications (
+	id            INTEGER PRIMARY KEY AUTOINCREMENT,
+	subscriber_id TEXT NOT NULL REFERENCES subscribers (id),
+	notification  TEXT NOT NULL,
+	created_at    TIMESTAMP NOT NULL
+);
+
+CREATE INDEX pending_notifications_subscriber ON pending_notifications (subscriber_id, id);
+`,
+	`
+ALTER TABLE subscribers ADD COLUMN language TEXT NOT NULL DEFAULT '';
+`,
+	`
+CREATE TABLE jobs (
+	id         INTEGER PRIMARY KEY AUTOINCREMENT,
+	trigger    TEXT NOT NULL,
+	status     TEXT NOT NULL,
+	stage      TEXT NOT NULL,
+	attempts   INTEGER NOT NULL DEFAULT 0,
+	run_id     INTEGER REFERENCES runs (id),
+	checkpoint TEXT NOT NULL DEFAULT '{}',
+	last_error TEXT NOT NULL DEFAULT '',
+	run_after  TIMESTAMP NOT NULL,
+	created_at TIMESTAMP NOT NULL,
+	updated_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX jobs_status ON jobs (status, run_after);
+
+CREATE TABLE job_requesters (
+	job_id      INTEGER NOT NULL REFERENCES jobs (id),
+	source_id   TEXT NOT NULL,
+	as_carousel INTEGER NOT NULL DEFAULT 0,
+	created_at  TIMESTAMP NOT NULL,
+	PRIMARY KEY (job_id, source_id)
+);
+
+CREATE TABLE dead_jobs (
+	id         INTEGER PRIMARY KEY AUTOINCREMENT,
+	job_id     INTEGER NOT NULL REFERENCES jobs (id),
+	trigger    TEXT NOT NULL,
+	stage      TEXT NOT NULL,
+	attempts   INTEGER NOT NULL,
+	error      TEXT NOT NULL,
+	checkpoint TEXT NOT NULL,
+	failed_at  TIMESTAMP NOT NULL
+);
+`,
+	`
+CREATE TABLE targets (
+	id         INTEGER PRIMARY KEY AUTOINCREMENT,
+	name       TEXT NOT NULL,
+	url        TEXT NOT NULL,
+	max_pages  INTEGER NOT NULL,
+	enabled    INTEGER NOT NULL DEFAULT 1,
+	created_at TIMESTAMP NOT NULL
+);
+`,
+	`
+CREATE TABLE feed_tokens (
+	source_id  TEXT PRIMARY KEY REFERENCES subscribers (id),
+	token      TEXT NOT NULL UNIQUE,
+	created_at TIMESTAMP NOT NULL
+);
+`,
+	`
+ALTER TABLE jobs ADD COLUMN trace_parent TEXT NOT NULL DEFAULT '';
+`,
+	`
+CREATE TABLE target_scrapes (
+	url        TEXT PRIMARY KEY,
+	scraped_at TIMESTAMP NOT NULL,
+	pages      INTEGER NOT NULL,
+	items      INTEGER NOT NULL
+);
+`,
+	`
+ALTER TABLE runs ADD COLUMN anomalies TEXT NOT NULL DEFAULT '[]';
+`,
+	`
+CREATE INDEX price_history_run_id ON price_history (run_id);
+`,
+}
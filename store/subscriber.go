@@ -0,0 +1,120 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/drifterz13/dino-noti/model"
+)
+
+var ErrNotFound = errors.New("not found")
+
+func (s *Store) UpsertSubscriber(id string, kind model.SourceKind) error {
+	_, err := s.db.Exec(
+		`INSERT INTO subscribers (id, kind, created_at) VALUES (?, ?, ?)
+		ON CONFLICT (id) DO NOTHING`,
+		id, kind, time.Now(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save subscriber %s: %w", id, err)
+	}
+	return nil
+}
+
+func (s *Store) Subscriber(id string) (*model.Subscriber, error) {
+	sub, err := scanSubscriber(s.db.QueryRow(
+		`SELECT id, kind, created_at, muted_until, active, timezone, quiet_start, quiet_end, digest, min_discount, last_digest_at, language FROM subscribers WHERE id = ?`,
+		id,
+	))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load subscriber %s: %w", id, err)
+	}
+	return sub, nil
+}
+
+func (s *Store) Subscribers() ([]model.Subscriber, error) {
+	rows, err := s.db.Query(`SELECT id, kind, created_at, muted_until, active, timezone, quiet_start, quiet_end, digest, min_discount, last_digest_at, language FROM subscribers ORDER BY created_at`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query subscribers: %w", err)
+	}
+	defer rows.Close()
+
+	var subscribers []model.Subscriber
+	for rows.Next() {
+		sub, err := scanSubscriber(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan subscriber: %w", err)
+		}
+		subscribers = append(subscribers, *sub)
+	}
+
+	return subscribers, rows.Err()
+}
+
+// SetMutedUntil pauses notifications for a subscriber; a zero time unmutes.
+func (s *Store) SetMutedUntil(id string, until time.Time) error {
+	var mutedUntil sql.NullTime
+	if !until.IsZero() {
+		mutedUntil = sql.NullTime{Time: until, Valid: true}
+	}
+
+	_, err := s.db.Exec(`UPDATE subscribers SET muted_until = ? WHERE id = ?`, mutedUntil, id)
+	if err != nil {
+		return fmt.Errorf("failed to mute subscriber %s: %w", id, err)
+	}
+	return nil
+}
+
+// SetActive turns push notifications on or off for a subscriber, e.g. on
+// follow and unfollow.
+func (s *Store) SetActive(id string, active bool) error {
+	_, err := s.db.Exec(`UPDATE subscribers SET active = ? WHERE id = ?`, active, id)
+	if err != nil {
+		return fmt.Errorf("failed to update subscriber %s: %w", id, err)
+	}
+	return nil
+}
+
+// SetPreferences replaces a subscriber's notification preferences.
+func (s *Store) SetPreferences(id string, prefs model.Preferences) error {
+	_, err := s.db.Exec(
+		`UPDATE subscribers SET language = ?, timezone = ?, quiet_start = ?, quiet_end = ?, digest = ?, min_discount = ? WHERE id = ?`,
+		prefs.Language, prefs.Timezone, prefs.QuietStart, prefs.QuietEnd, prefs.Digest, prefs.MinDiscount, id,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update preferences of %s: %w", id, err)
+	}
+	return nil
+}
+
+func (s *Store) SetLastDigestAt(id string, at time.Time) error {
+	_, err := s.db.Exec(`UPDATE subscribers SET last_digest_at = ? WHERE id = ?`, at, id)
+	if err != nil {
+		return fmt.Errorf("failed to update last digest of %s: %w", id, err)
+	}
+	return nil
+}
+
+func scanSubscriber(row scanner) (*model.Subscriber, error) {
+	var (
+		sub          model.Subscriber
+		mutedUntil   sql.NullTime
+		lastDigestAt sql.NullTime
+	)
+	err := row.Scan(
+		&sub.ID, &sub.Kind, &sub.CreatedAt, &mutedUntil, &sub.Active,
+		&sub.Preferences.Timezone, &sub.Preferences.QuietStart, &sub.Preferences.QuietEnd,
+		&sub.Preferences.Digest, &sub.Preferences.MinDiscount, &lastDigestAt, &sub.Preferences.Language,
+	)
+	if err != nil {
+		return nil, err
+	}
+	sub.MutedUntil = mutedUntil.Time
+	sub.LastDigestAt = lastDigestAt.Time
+	return &sub, nil
+}
@@ -0,0 +1,82 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/drifterz13/dino-noti/model"
+)
+
+func (s *Store) Targets() ([]model.Target, error) {
+	rows, err := s.db.Query(`SELECT id, name, url, max_pages, enabled, created_at FROM targets ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query targets: %w", err)
+	}
+	defer rows.Close()
+
+	var targets []model.Target
+	for rows.Next() {
+		var t model.Target
+		if err := rows.Scan(&t.ID, &t.Name, &t.URL, &t.MaxPages, &t.Enabled, &t.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan target: %w", err)
+		}
+		targets = append(targets, t)
+	}
+
+	return targets, rows.Err()
+}
+
+func (s *Store) Target(id int64) (*model.Target, error) {
+	var t model.Target
+	err := s.db.QueryRow(
+		`SELECT id, name, url, max_pages, enabled, created_at FROM targets WHERE id = ?`,
+		id,
+	).Scan(&t.ID, &t.Name, &t.URL, &t.MaxPages, &t.Enabled, &t.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load target %d: %w", id, err)
+	}
+	return &t, nil
+}
+
+func (s *Store) AddTarget(target *model.Target) error {
+	target.CreatedAt = time.Now()
+	res, err := s.db.Exec(
+		`INSERT INTO targets (name, url, max_pages, enabled, created_at) VALUES (?, ?, ?, ?, ?)`,
+		target.Name, target.URL, target.MaxPages, target.Enabled, target.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to add target %s: %w", target.Name, err)
+	}
+
+	target.ID, err = res.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to read target id: %w", err)
+	}
+	return nil
+}
+
+// UpdateTarget returns ErrNotFound when there is no target with the ID.
+func (s *Store) UpdateTarget(target model.Target) error {
+	res, err := s.db.Exec(
+		`UPDATE targets SET name = ?, url = ?, max_pages = ?, enabled = ? WHERE id = ?`,
+		target.Name, target.URL, target.MaxPages, target.Enabled, target.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update target %d: %w", target.ID, err)
+	}
+	return expectRow(res)
+}
+
+// DeleteTarget returns ErrNotFound when there is no target with the ID.
+func (s *Store) DeleteTarget(id int64) error {
+	res, err := s.db.Exec(`DELETE FROM targets WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete target %d: %w", id, err)
+	}
+	return expectRow(res)
+}
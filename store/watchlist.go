@@ -0,0 +1,101 @@
+package store
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/drifterz13/dino-noti/model"
+)
+
+// AddWatch adds a model to a source's watchlist. It reports false when the
+// model was already on it, keeping whoever added it first.
+func (s *Store) AddWatch(sourceID string, entry model.WatchEntry) (bool, error) {
+	res, err := s.db.Exec(
+		`INSERT INTO watchlist (source_id, model, added_by, added_by_name, created_at) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (source_id, model) DO NOTHING`,
+		sourceID, entry.Model, entry.AddedBy, entry.AddedByName, time.Now(),
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to watch %s for %s: %w", entry.Model, sourceID, err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to watch %s for %s: %w", entry.Model, sourceID, err)
+	}
+	return n > 0, nil
+}
+
+// RemoveWatch reports whether the model was on the source's watchlist.
+func (s *Store) RemoveWatch(sourceID, model string) (bool, error) {
+	res, err := s.db.Exec(
+		`DELETE FROM watchlist WHERE source_id = ? AND model = ? COLLATE NOCASE`,
+		sourceID, model,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to unwatch %s for %s: %w", model, sourceID, err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to unwatch %s for %s: %w", model, sourceID, err)
+	}
+	return n > 0, nil
+}
+
+func (s *Store) Watchlist(sourceID string) ([]model.WatchEntry, error) {
+	return s.queryWatchEntries(
+		`SELECT source_id, model, added_by, added_by_name, created_at FROM watchlist WHERE source_id = ? ORDER BY model`,
+		sourceID,
+	)
+}
+
+// Watchlists returns the entries of every source's watchlist.
+func (s *Store) Watchlists() ([]model.WatchEntry, error) {
+	return s.queryWatchEntries(
+		`SELECT source_id, model, added_by, added_by_name, created_at FROM watchlist ORDER BY source_id, model`,
+	)
+}
+
+func (s *Store) queryWatchEntries(query string, args ...any) ([]model.WatchEntry, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query watchlist: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []model.WatchEntry
+	for rows.Next() {
+		var entry model.WatchEntry
+		if err := rows.Scan(&entry.SourceID, &entry.Model, &entry.AddedBy, &entry.AddedByName, &entry.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan watchlist entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
+
+// WatchedModels returns every model on any watchlist.
+func (s *Store) WatchedModels() ([]string, error) {
+	return s.queryStrings(`SELECT DISTINCT model FROM watchlist ORDER BY model`)
+}
+
+func (s *Store) queryStrings(query string, args ...any) ([]string, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query: %w", err)
+	}
+	defer rows.Close()
+
+	var values []string
+	for rows.Next() {
+		var value string
+		if err := rows.Scan(&value); err != nil {
+			return nil, fmt.Errorf("failed to scan: %w", err)
+		}
+		values = append(values, value)
+	}
+
+	return values, rows.Err()
+}
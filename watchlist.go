@@ -0,0 +1,87 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/drifterz13/dino-noti/model"
+)
+
+func watchlist(args []string) error {
+	flags := flag.NewFlagSet("watchlist", flag.ExitOnError)
+	source := flags.String("source", "", "LINE user, group or room `ID` of the watchlist")
+	format := flags.String("format", "table", "output `format` of list: table, json or csv")
+	flags.Usage = usageFor(flags, "watchlist [flags] list | add MODEL... | remove MODEL...",
+		"Show or change watchlists. list shows every chat's watchlist unless -source is set; add and remove need -source.")
+	flags.Parse(args)
+
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return errors.New("missing watchlist action")
+	}
+	action, models := flags.Arg(0), flags.Args()[1:]
+
+	srv, close, err := openService()
+	if err != nil {
+		return err
+	}
+	defer close()
+
+	switch action {
+	case "list":
+		if err := checkFormat(*format); err != nil {
+			return err
+		}
+		var entries []model.WatchEntry
+		if *source != "" {
+			entries, err = srv.Watchlist(*source)
+		} else {
+			entries, err = srv.Watchlists()
+		}
+		if err != nil {
+			return err
+		}
+		out := []watchEntry{}
+		for _, e := range entries {
+			out = append(out, newWatchEntry(e))
+		}
+		return writeOutput(os.Stdout, *format, out, watchEntriesTable(out))
+
+	case "add", "remove":
+		if *source == "" || len(models) == 0 {
+			flags.Usage()
+			return fmt.Errorf("%s needs -source and at least one model", action)
+		}
+		for _, name := range models {
+			if action == "add" {
+				entry, added, err := srv.Watch(model.WatchEntry{SourceID: *source, Model: name, AddedByName: "cli"})
+				if err != nil {
+					return err
+				}
+				if added {
+					fmt.Printf("Added %s\n", entry.Model)
+				} else {
+					fmt.Printf("%s is already on the watchlist\n", entry.Model)
+				}
+				continue
+			}
+
+			removed, err := srv.Unwatch(*source, name)
+			if err != nil {
+				return err
+			}
+			if removed {
+				fmt.Printf("Removed %s\n", name)
+			} else {
+				fmt.Printf("%s is not on the watchlist\n", name)
+			}
+		}
+		return nil
+
+	default:
+		flags.Usage()
+		return fmt.Errorf("unknown watchlist action %q", action)
+	}
+}